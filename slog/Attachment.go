@@ -0,0 +1,143 @@
+package slog
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+)
+
+// defaultMaxAttachmentBytes is the attachment size cap until SetMaxAttachmentBytes is called.
+const defaultMaxAttachmentBytes = 64 * 1024
+
+// unsafeFileNameChars matches characters not kept when an attachment name is used in a file name.
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// attachment is a binary blob added with WithAttachment.
+type attachment struct {
+	name         string
+	data         []byte // Already capped to the logger's maximum
+	originalSize int
+
+	// The text output writes the blob to a sidecar file once and reuses its path.
+	sidecarOnce sync.Once
+	sidecarPath string
+	sidecarErr  error
+}
+
+// WithAttachment returns a derived Logger whose lines carry a small binary blob,
+// such as a failing payload, for later inspection.
+//
+// Sinks with structured output (e.g. SQLite, journald) and JSON output (see
+// NewNDJSONLogger, whose text copy shows the same) get the blob base64-encoded
+// under an attachments field. Text-only output can't hold binary data, so the blob is
+// written to a sidecar file in the attachment directory (see SetAttachmentDir) and
+// the line carries attachment.<name>=<path>. Blobs larger than the maximum (see
+// SetMaxAttachmentBytes) are truncated, and the line notes their original size.
+func (l *Logger) WithAttachment(name string, data []byte) *Logger {
+	derived := l.derive()
+	size := len(data)
+	if size > derived.maxAttachmentBytes() {
+		data = data[:derived.maxAttachmentBytes()]
+	}
+	derived.attachments = append(derived.attachments, &attachment{
+		name:         name,
+		data:         append([]byte(nil), data...),
+		originalSize: size,
+	})
+	return derived
+}
+
+// SetMaxAttachmentBytes sets the size above which attachments added afterwards
+// with WithAttachment are truncated. Zero or a negative n restores the 64KiB default.
+// It's thread-safe.
+func (l *Logger) SetMaxAttachmentBytes(n int) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.maxAttachment = n
+}
+
+// SetAttachmentDir sets the directory text output writes attachment sidecar files
+// to. An empty dir, the default, means os.TempDir().
+// It's thread-safe.
+func (l *Logger) SetAttachmentDir(dir string) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.attachmentDir = dir
+}
+
+// maxAttachmentBytes returns the effective attachment size cap.
+func (l *Logger) maxAttachmentBytes() int {
+	l.settingsMutex.RLock()
+	defer l.settingsMutex.RUnlock()
+	if l.maxAttachment <= 0 {
+		return defaultMaxAttachmentBytes
+	}
+	return l.maxAttachment
+}
+
+// attachmentFields returns the fields describing the logger's attachments: base64
+// data for sinks and JSON output, sidecar file paths for text-only output.
+func (l *Logger) attachmentFields() []Field {
+	if len(l.attachments) == 0 {
+		return nil
+	}
+
+	truncated := make(map[string]int)
+	for _, a := range l.attachments {
+		if a.originalSize > len(a.data) {
+			truncated[a.name] = a.originalSize
+		}
+	}
+
+	if l.sink != nil || l.jsonLogger != nil {
+		encoded := make(map[string]string, len(l.attachments))
+		for _, a := range l.attachments {
+			encoded[a.name] = base64.StdEncoding.EncodeToString(a.data)
+		}
+		fields := []Field{{Key: "attachments", Value: encoded}}
+		if len(truncated) > 0 {
+			fields = append(fields, Field{Key: "attachments_truncated_from", Value: truncated})
+		}
+		return fields
+	}
+
+	l.settingsMutex.RLock()
+	dir := l.attachmentDir
+	l.settingsMutex.RUnlock()
+
+	var fields []Field
+	for _, a := range l.attachments {
+		key := "attachment." + a.name
+		path, err := a.sidecar(dir)
+		if err != nil {
+			fields = append(fields, Field{Key: key, Value: fmt.Sprintf("<error: %v>", err)})
+		} else {
+			fields = append(fields, Field{Key: key, Value: path})
+		}
+		if size, ok := truncated[a.name]; ok {
+			fields = append(fields, Field{Key: key + ".truncated_from", Value: size})
+		}
+	}
+	return fields
+}
+
+// sidecar writes the attachment to a new file in dir the first time it is called,
+// and returns that file's path.
+func (a *attachment) sidecar(dir string) (string, error) {
+	a.sidecarOnce.Do(func() {
+		file, err := os.CreateTemp(dir, "slog-attachment-"+unsafeFileNameChars.ReplaceAllString(a.name, "_")+"-*.bin")
+		if err != nil {
+			a.sidecarErr = err
+			return
+		}
+		defer file.Close()
+		if _, err := file.Write(a.data); err != nil {
+			a.sidecarErr = err
+			return
+		}
+		a.sidecarPath = file.Name()
+	})
+	return a.sidecarPath, a.sidecarErr
+}
@@ -0,0 +1,92 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLoggerAttachmentStructured ensures sinks receive attachments base64-encoded
+// under an attachments field, with oversized blobs truncated and noted.
+func TestLoggerAttachmentStructured(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	logger, sink := newCaptureLogger("Webhook")
+	logger.SetMaxAttachmentBytes(4)
+	payload := []byte{0x00, 0xff, 0x10, 0x80, 0x7f, 0x01}
+	logger.WithAttachment("payload", payload).WithAttachment("sig", []byte{0xAB}).Error("Rejected payload")
+
+	entries := sink.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	expected := []Field{
+		{Key: "attachments", Value: map[string]string{
+			"payload": base64.StdEncoding.EncodeToString(payload[:4]),
+			"sig":     base64.StdEncoding.EncodeToString([]byte{0xAB}),
+		}},
+		{Key: "attachments_truncated_from", Value: map[string]int{"payload": 6}},
+	}
+	if !reflect.DeepEqual(entries[0].Fields, expected) {
+		t.Errorf("Expected fields %v, got %v", expected, entries[0].Fields)
+	}
+}
+
+// TestLoggerAttachmentSidecar ensures text output writes the attachment to a sidecar
+// file once and references its path on every line.
+func TestLoggerAttachmentSidecar(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	parent := newTestLogger(&buf, "Webhook")
+	parent.SetAttachmentDir(dir)
+	payload := []byte("\x00binary\xffpayload")
+	logger := parent.WithAttachment("bad/payload", payload)
+
+	logger.Error("Rejected payload")
+	logger.Warn("Still rejected")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", lines)
+	}
+	const marker = " attachment.bad/payload="
+	idx := strings.Index(lines[0], marker)
+	if idx < 0 {
+		t.Fatalf("Expected the line to reference the sidecar file, got %q", lines[0])
+	}
+	path := lines[0][idx+len(marker):]
+	if !strings.HasSuffix(lines[1], marker+path) {
+		t.Errorf("Expected both lines to reference the same sidecar file, got %q", lines[1])
+	}
+	if filepath.Dir(path) != dir || !strings.HasPrefix(filepath.Base(path), "slog-attachment-bad_payload-") {
+		t.Errorf("Expected a sanitised sidecar file name in %s, got %s", dir, path)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(written, payload) {
+		t.Errorf("Expected the sidecar file to hold the payload, got %q (%v)", written, err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 1 {
+		t.Errorf("Expected a single sidecar file, got %d", len(files))
+	}
+
+	// The parent logger has no attachment.
+	buf.Reset()
+	parent.Error("No attachment")
+	if output := strings.TrimSpace(buf.String()); output != "[ERROR][Webhook] No attachment" {
+		t.Errorf("Expected no attachment on the parent, got %q", output)
+	}
+}
@@ -0,0 +1,26 @@
+package slog
+
+// LogBatch logs summary followed by one line per detail at level, written as a
+// single block so the batch stays together in the output even when other
+// goroutines are logging. The summary gets a batch_size field with the number of
+// details. The batch is filtered and sampled as a whole, like a single line;
+// suppressions still apply to each line.
+//
+// Unlike Info and friends, summary and details are plain strings, not format strings.
+func (l *Logger) LogBatch(level LogLevel, summary string, details []string) {
+	if !l.admits(level) {
+		return
+	}
+
+	t := now()
+	entries := make([]Entry, 0, len(details)+1)
+	if entry, ok := l.newEntry(t, level, summary, Field{Key: "batch_size", Value: len(details)}); ok {
+		entries = append(entries, entry)
+	}
+	for _, detail := range details {
+		if entry, ok := l.newEntry(t, level, detail); ok {
+			entries = append(entries, entry)
+		}
+	}
+	l.writeBlock(entries)
+}
@@ -0,0 +1,80 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestLoggerLogBatch ensures a batch is written as a contiguous block, summary
+// first with its batch_size field, even while other goroutines are logging.
+func TestLoggerLogBatch(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Import")
+
+	const batches, details = 20, 5
+	var wg sync.WaitGroup
+	for b := 0; b < batches; b++ {
+		wg.Add(2)
+		go func(b int) {
+			defer wg.Done()
+			var lines []string
+			for d := 0; d < details; d++ {
+				lines = append(lines, fmt.Sprintf("batch %d row %d", b, d))
+			}
+			logger.LogBatch(INFO, fmt.Sprintf("batch %d imported", b), lines)
+		}(b)
+		go func(b int) {
+			defer wg.Done()
+			logger.Info("unrelated %d", b)
+		}(b)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != batches*(details+2) {
+		t.Fatalf("Expected %d lines, got %d", batches*(details+2), len(lines))
+	}
+	for i := 0; i < len(lines); i++ {
+		var b int
+		if _, err := fmt.Sscanf(lines[i], "[INFO][Import] batch %d imported", &b); err != nil {
+			continue
+		}
+		if !strings.HasSuffix(lines[i], fmt.Sprintf(" batch_size=%d", details)) {
+			t.Errorf("Expected the summary to carry batch_size=%d, got %q", details, lines[i])
+		}
+		for d := 0; d < details; d++ {
+			expected := fmt.Sprintf("[INFO][Import] batch %d row %d", b, d)
+			if i+1+d >= len(lines) || lines[i+1+d] != expected {
+				t.Fatalf("Expected line %d to be %q, the batch was interleaved:\n%s", i+1+d, expected, buf.String())
+			}
+		}
+	}
+}
+
+// TestLoggerLogBatchFiltered ensures a filtered batch writes nothing and counts as one filtered line.
+func TestLoggerLogBatchFiltered(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	logger, sink := newCaptureLogger("Import")
+	logger.LogBatch(DEBUG, "summary", []string{"a", "b"})
+
+	if entries := sink.Entries(); len(entries) != 0 {
+		t.Errorf("Expected no entries, got %d", len(entries))
+	}
+	if filtered := logger.FilteredStats()[DEBUG]; filtered != 1 {
+		t.Errorf("Expected 1 filtered DEBUG line, got %d", filtered)
+	}
+}
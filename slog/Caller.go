@@ -0,0 +1,64 @@
+package slog
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// loggerMethodPrefixes are how runtime names the methods that log on behalf of
+// their caller, e.g. "github.com/giles-m-thompson/slog/slog.(*Logger).Info" for Info.
+var loggerMethodPrefixes = []string{
+	reflect.TypeOf(Logger{}).PkgPath() + ".(*Logger).",
+	reflect.TypeOf(Op{}).PkgPath() + ".(*Op).",
+	reflect.TypeOf(Retry{}).PkgPath() + ".(*Retry).",
+}
+
+// SetReportFunction controls whether each line gets a func field with the short
+// name of the function that called the logging method (e.g. processOrder, or
+// (*Server).handle for a method). It's cheaper than full file:line caller info.
+// It's thread-safe.
+func (l *Logger) SetReportFunction(report bool) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.reportFunction = report
+}
+
+// callerFunction returns the short name of the first function on the stack that
+// isn't a Logger (or Op, or Retry) method, i.e. whoever called Info, ErrorAt, etc.
+func callerFunction() string {
+	var pcs [16]uintptr
+	n := runtime.Callers(2, pcs[:]) // Skip runtime.Callers and callerFunction
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isLoggerMethod(frame.Function) {
+			return shortFunctionName(frame.Function)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// isLoggerMethod reports whether function is one of loggerMethodPrefixes' methods.
+func isLoggerMethod(function string) bool {
+	for _, prefix := range loggerMethodPrefixes {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// shortFunctionName strips the package path from a function name as reported by
+// runtime, e.g. "example.com/shop/orders.processOrder" becomes "processOrder".
+func shortFunctionName(name string) string {
+	if slash := strings.LastIndex(name, "/"); slash >= 0 {
+		name = name[slash+1:]
+	}
+	if dot := strings.Index(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+	return name
+}
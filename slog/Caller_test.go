@@ -0,0 +1,59 @@
+package slog
+
+import (
+	"testing"
+)
+
+// processOrder stands in for application code that logs.
+func processOrder(logger *Logger) {
+	logger.Info("Processing order")
+	logger.WarnAt(now(), "Order delayed")
+}
+
+// TestLoggerReportFunction ensures the func field names the function that called
+// the logging method, through both the plain and the explicit timestamp methods.
+func TestLoggerReportFunction(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	logger, sink := newCaptureLogger("Shop")
+	processOrder(logger)
+
+	logger.SetReportFunction(true)
+	processOrder(logger)
+	processOrder(logger.WithFields(Field{Key: "order", Value: 42}))
+
+	entries := sink.Entries()
+	if len(entries) != 6 {
+		t.Fatalf("Expected 6 entries, got %d", len(entries))
+	}
+	for _, entry := range entries[:2] {
+		if len(entry.Fields) != 0 {
+			t.Errorf("Expected no fields before SetReportFunction, got %v", entry.Fields)
+		}
+	}
+	for _, entry := range entries[2:] {
+		last := entry.Fields[len(entry.Fields)-1]
+		if last.Key != "func" || last.Value != "processOrder" {
+			t.Errorf("Expected func=processOrder for %q, got %v", entry.Message, entry.Fields)
+		}
+	}
+}
+
+// TestShortFunctionName ensures package paths are stripped from function names.
+func TestShortFunctionName(t *testing.T) {
+	testCases := map[string]string{
+		"main.main":                             "main",
+		"example.com/shop/orders.processOrder":  "processOrder",
+		"example.com/shop/orders.(*Server).run": "(*Server).run",
+		"example.com/shop.v2/orders.Test.func1": "Test.func1",
+	}
+	for name, expected := range testCases {
+		if short := shortFunctionName(name); short != expected {
+			t.Errorf("shortFunctionName(%q) = %q, expected %q", name, short, expected)
+		}
+	}
+}
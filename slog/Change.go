@@ -0,0 +1,40 @@
+package slog
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// LogChange logs that field changed from oldVal to newVal, e.g.
+//
+//	logger.LogChange(slog.INFO, "max_connections", 100, 200)
+//
+// writes "[INFO] max_connections changed from 100 to 200 changed_field=max_connections old=100 new=200",
+// so audit-style records share one structure. Nothing is logged if the values are
+// equal (per reflect.DeepEqual), unless SetLogUnchanged(true) was called. Both
+// values are redacted if field is sensitive (see SetSensitiveFieldKeys).
+func (l *Logger) LogChange(level LogLevel, field string, oldVal, newVal interface{}) {
+	l.settingsMutex.RLock()
+	logUnchanged := l.logUnchanged
+	l.settingsMutex.RUnlock()
+	if !logUnchanged && reflect.DeepEqual(oldVal, newVal) {
+		return
+	}
+	if l.isSensitive(field) { // The values are in the message too, so redact them here
+		oldVal, newVal = redactedValue, redactedValue
+	}
+	l.logFields(level, fmt.Sprintf("%s changed from %s to %s", field, formatFieldValue(oldVal), formatFieldValue(newVal)),
+		Field{Key: "changed_field", Value: field},
+		Field{Key: "old", Value: oldVal},
+		Field{Key: "new", Value: newVal},
+	)
+}
+
+// SetLogUnchanged controls whether LogChange logs "changes" where the old and new
+// values are equal. The default is false, so they are skipped.
+// It's thread-safe.
+func (l *Logger) SetLogUnchanged(log bool) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.logUnchanged = log
+}
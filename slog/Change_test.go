@@ -0,0 +1,62 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestLoggerLogChange ensures changes are logged with a readable message and the
+// changed_field, old and new fields.
+func TestLoggerLogChange(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	logger, sink := newCaptureLogger("Config")
+	logger.LogChange(WARN, "max_connections", 100, 200)
+	logger.LogChange(DEBUG, "timeout", "1s", "2s")
+
+	entries := sink.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Level != WARN || entries[0].Message != "max_connections changed from 100 to 200" {
+		t.Errorf("Unexpected entry %v", entries[0])
+	}
+	expected := []Field{
+		{Key: "changed_field", Value: "max_connections"},
+		{Key: "old", Value: 100},
+		{Key: "new", Value: 200},
+	}
+	if !reflect.DeepEqual(entries[0].Fields, expected) {
+		t.Errorf("Expected fields %v, got %v", expected, entries[0].Fields)
+	}
+	if line := logger.formatText(entries[0]); line != "[WARN][Config] max_connections changed from 100 to 200 changed_field=max_connections old=100 new=200" {
+		t.Errorf("Unexpected line %q", line)
+	}
+}
+
+// TestLoggerLogChangeUnchanged ensures equal values are skipped unless SetLogUnchanged(true) was called.
+func TestLoggerLogChangeUnchanged(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	logger, sink := newCaptureLogger("Config")
+	logger.LogChange(INFO, "hosts", []string{"a", "b"}, []string{"a", "b"})
+	logger.LogChange(INFO, "retries", 3, 3)
+	if n := len(sink.Entries()); n != 0 {
+		t.Errorf("Expected unchanged values to be skipped, got %d entries", n)
+	}
+
+	logger.SetLogUnchanged(true)
+	logger.LogChange(INFO, "retries", 3, 3)
+	entries := sink.Entries()
+	if len(entries) != 1 || entries[0].Message != "retries changed from 3 to 3" {
+		t.Errorf("Expected the unchanged value to be logged, got %v", entries)
+	}
+}
@@ -0,0 +1,44 @@
+package slog
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time. Every time-dependent feature of the package
+// (timestamps, event IDs, sink event times) reads the time through the package
+// clock, so a test can control all of them together with SetClock.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the default Clock, backed by time.Now.
+type realClock struct{}
+
+// Now returns the current local time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// This mutex ensures thread-safe access to the package clock
+var clockMutex sync.RWMutex
+var clock Clock = realClock{}
+
+// SetClock replaces the clock used by ALL Logger instances.
+// Passing nil restores the real clock.
+// It's thread-safe.
+func SetClock(c Clock) {
+	clockMutex.Lock()
+	defer clockMutex.Unlock()
+	if c == nil {
+		c = realClock{}
+	}
+	clock = c
+}
+
+// now returns the current time according to the package clock.
+func now() time.Time {
+	clockMutex.RLock()
+	defer clockMutex.RUnlock()
+	return clock.Now()
+}
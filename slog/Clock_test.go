@@ -0,0 +1,100 @@
+package slog
+
+import (
+	"bytes"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock that only moves when Advance is called.
+type fakeClock struct {
+	mutex   sync.Mutex
+	current time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.current
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.current = c.current.Add(d)
+}
+
+// eventIDMillis decodes the 48-bit millisecond timestamp at the start of a UUIDv7.
+func eventIDMillis(t *testing.T, id string) int64 {
+	millis, err := strconv.ParseInt(strings.Replace(id[:13], "-", "", 1), 16, 64)
+	if err != nil {
+		t.Fatalf("Expected a hex timestamp at the start of %q: %v", id, err)
+	}
+	return millis
+}
+
+// TestSetClock ensures timestamps, event IDs and sink event times all follow the
+// package clock and move together when a fake clock is advanced.
+func TestSetClock(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetClock(nil)
+	})
+	SetGlobalMinLevel(INFO)
+
+	start := time.Date(2030, time.January, 2, 3, 4, 5, 0, time.Local)
+	fake := &fakeClock{current: start}
+	SetClock(fake)
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Clock")
+	logger.timestamps = true
+	// A private generator, so the fake time doesn't leak into the shared one.
+	ids := &eventIDGenerator{random: rand.New(rand.NewSource(1))}
+	client := &mockCloudWatchClient{}
+	sink := NewCloudWatchSink(client, "group", "stream")
+
+	steps := []time.Duration{0, 90 * time.Second, 2 * time.Hour}
+	for i, step := range steps {
+		fake.Advance(step)
+		expected := fake.Now()
+
+		buf.Reset()
+		logger.Info("Tick %d", i)
+		expectedLine := expected.Format(timestampLayout) + " [INFO][Clock] Tick " + strconv.Itoa(i)
+		if output := strings.TrimSpace(buf.String()); output != expectedLine {
+			t.Errorf("Expected timestamped line %q, got %q", expectedLine, output)
+		}
+
+		expectedMillis := expected.UnixNano() / int64(time.Millisecond)
+		if millis := eventIDMillis(t, ids.next()); millis != expectedMillis {
+			t.Errorf("Expected event ID time %d, got %d", expectedMillis, millis)
+		}
+
+		sink.Info("Tick %d", i)
+	}
+	sink.Close()
+
+	if len(client.calls) != 1 || len(client.calls[0].LogEvents) != len(steps) {
+		t.Fatalf("Expected one batch of %d events, got %+v", len(steps), client.calls)
+	}
+	elapsed := start
+	for i, step := range steps {
+		elapsed = elapsed.Add(step)
+		expectedMillis := elapsed.UnixNano() / int64(time.Millisecond)
+		if ts := client.calls[0].LogEvents[i].Timestamp; ts != expectedMillis {
+			t.Errorf("Expected sink event %d at %d, got %d", i, expectedMillis, ts)
+		}
+	}
+
+	// Restoring the real clock brings timestamps back to the present.
+	SetClock(nil)
+	if drift := time.Since(now()); drift < 0 || drift > time.Minute {
+		t.Errorf("Expected the real clock after SetClock(nil), got a drift of %s", drift)
+	}
+}
@@ -0,0 +1,56 @@
+package slog
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrClosed is reported for lines logged after the Logger was closed.
+var ErrClosed = errors.New("logger is closed")
+
+// ClosedBehavior is what a Logger does with lines logged after Close.
+type ClosedBehavior int
+
+const (
+	ReportError  ClosedBehavior = iota // Drop the line and report ErrClosed to the error handler (the default)
+	DropSilently                       // Drop the line
+	PanicInDebug                       // Panic with ErrClosed if the global minimum level is DEBUG or finer, otherwise ReportError
+)
+
+// SetClosedBehavior sets what happens to lines logged after Close, which usually
+// means shutdown happened in the wrong order. The default is ReportError, so the
+// lines aren't lost without a trace; PanicInDebug makes the mistake impossible to
+// miss during development. See SetErrorHandler.
+// It's thread-safe.
+func (l *Logger) SetClosedBehavior(behavior ClosedBehavior) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.closedBehavior = behavior
+}
+
+// isClosed reports whether the logger, or the Logger owning its output, has been
+// closed, in which case the line at level is dropped as configured by SetClosedBehavior.
+func (l *Logger) isClosed(level LogLevel) bool {
+	if atomic.LoadUint32(&l.closed) == 0 && atomic.LoadUint32(&l.owner().closed) == 0 {
+		return false
+	}
+	l.settingsMutex.RLock()
+	behavior := l.closedBehavior
+	l.settingsMutex.RUnlock()
+
+	switch behavior {
+	case DropSilently:
+		return true
+	case PanicInDebug:
+		if GetGlobalMinLevel() >= DEBUG {
+			panic(ErrClosed)
+		}
+	}
+	if l.component != "" {
+		reportError(fmt.Errorf("%w: dropped %s line from %s", ErrClosed, level, l.component))
+	} else {
+		reportError(fmt.Errorf("%w: dropped %s line", ErrClosed, level))
+	}
+	return true
+}
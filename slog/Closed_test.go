@@ -0,0 +1,115 @@
+package slog
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestLoggerClosedBehavior ensures lines logged after Close are dropped, and
+// reported or turned into a panic as configured.
+func TestLoggerClosedBehavior(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+
+	testCases := []struct {
+		name          string
+		behavior      ClosedBehavior
+		globalLevel   LogLevel
+		expectReports int
+		expectPanic   bool
+	}{
+		{"ReportError", ReportError, INFO, 1, false},
+		{"DropSilently", DropSilently, INFO, 0, false},
+		{"PanicInDebug at DEBUG", PanicInDebug, DEBUG, 0, true},
+		{"PanicInDebug at INFO", PanicInDebug, INFO, 1, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			SetGlobalMinLevel(tc.globalLevel)
+			recorder := recordErrors(t)
+			logger, sink := newCaptureLogger("Shutdown")
+			logger.SetClosedBehavior(tc.behavior)
+
+			logger.Info("Before close")
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Expected Close to succeed, got %v", err)
+			}
+
+			var recovered interface{}
+			func() {
+				defer func() { recovered = recover() }()
+				logger.Info("After close")
+			}()
+
+			if tc.expectPanic != (recovered != nil) {
+				t.Errorf("Expected panic=%v, recovered %v", tc.expectPanic, recovered)
+			}
+			if err, ok := recovered.(error); ok && !errors.Is(err, ErrClosed) {
+				t.Errorf("Expected to panic with ErrClosed, got %v", err)
+			}
+			if entries := sink.Entries(); len(entries) != 1 {
+				t.Errorf("Expected only the line before Close to be written, got %d", len(entries))
+			}
+			errs := recorder.Errors()
+			if len(errs) != tc.expectReports {
+				t.Fatalf("Expected %d reported errors, got %v", tc.expectReports, errs)
+			}
+			for _, err := range errs {
+				if !errors.Is(err, ErrClosed) || err.Error() != "logger is closed: dropped INFO line from Shutdown" {
+					t.Errorf("Expected an ErrClosed report naming the level and component, got %v", err)
+				}
+			}
+		})
+	}
+}
+
+// TestLoggerClosedDerived ensures closing a Logger also closes the Loggers derived
+// from it, before or after, while closing a derived Logger leaves its parent open.
+func TestLoggerClosedDerived(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+	recorder := recordErrors(t)
+
+	parent, sink := newCaptureLogger("Shutdown")
+	before := parent.WithFields(Field{Key: "worker", Value: 1})
+	before.Close()
+	parent.Info("Parent still open")
+	if entries := sink.Entries(); len(entries) != 1 {
+		t.Fatalf("Expected closing the child to leave the parent open, got %d entries", len(entries))
+	}
+
+	child := parent.WithFields(Field{Key: "worker", Value: 2})
+	parent.Close()
+	child.Info("After close")
+	parent.WithFields(Field{Key: "worker", Value: 3}).Info("After close")
+	if entries := sink.Entries(); len(entries) != 1 {
+		t.Errorf("Expected nothing written through derived Loggers after Close, got %d entries", len(entries))
+	}
+	if errs := recorder.Errors(); len(errs) != 2 || !errors.Is(errs[0], ErrClosed) {
+		t.Errorf("Expected both dropped lines to be reported, got %v", errs)
+	}
+}
+
+// TestLoggerClosedFilteredFirst ensures a line below the global minimum level
+// isn't reported as dropped after Close, since it would never have been written.
+func TestLoggerClosedFilteredFirst(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+	recorder := recordErrors(t)
+
+	logger, _ := newCaptureLogger("Shutdown")
+	logger.Close()
+	logger.Debug("Filtered anyway")
+	if errs := recorder.Errors(); len(errs) != 0 {
+		t.Errorf("Expected no reports for a filtered line, got %v", errs)
+	}
+}
@@ -0,0 +1,248 @@
+package slog
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CloudWatch Logs limits for a single PutLogEvents call, and how the sink works within them.
+const (
+	cloudWatchMaxBatchEvents   = 10000
+	cloudWatchMaxBatchBytes    = 1048576
+	cloudWatchEventOverhead    = 26 // Bytes CloudWatch adds to each event's size
+	cloudWatchMaxTokenRetries  = 3
+	cloudWatchFlushInterval    = 5 * time.Second
+	cloudWatchMaxBatchSpan     = 24 * time.Hour // Oldest to newest event in one call
+	cloudWatchMaxQueuedBatches = 8              // Full batches held while sending falls behind
+)
+
+// CloudWatchLogEvent is a single event in a PutLogEvents batch.
+type CloudWatchLogEvent struct {
+	Message   string
+	Timestamp int64 // Milliseconds since the Unix epoch, as CloudWatch requires
+}
+
+// PutLogEventsInput mirrors the fields of the CloudWatch Logs PutLogEvents request used by the sink.
+type PutLogEventsInput struct {
+	LogGroupName  string
+	LogStreamName string
+	SequenceToken *string // nil for the first call to a new stream
+	LogEvents     []CloudWatchLogEvent
+}
+
+// CloudWatchLogsClient is the subset of the CloudWatch Logs API the sink needs.
+// This package doesn't depend on the AWS SDK; wrap the SDK client in a small adapter
+// that converts the input and returns the response's NextSequenceToken.
+// When the service answers with InvalidSequenceTokenException the adapter should
+// return an *InvalidSequenceTokenError so the sink can retry with the expected token.
+type CloudWatchLogsClient interface {
+	PutLogEvents(input *PutLogEventsInput) (nextSequenceToken *string, err error)
+}
+
+// InvalidSequenceTokenError reports that PutLogEvents was called with a stale sequence token.
+type InvalidSequenceTokenError struct {
+	ExpectedSequenceToken *string
+}
+
+// Error implements the error interface.
+func (e *InvalidSequenceTokenError) Error() string {
+	if e.ExpectedSequenceToken == nil {
+		return "invalid sequence token, expected none"
+	}
+	return fmt.Sprintf("invalid sequence token, expected %s", *e.ExpectedSequenceToken)
+}
+
+// NewCloudWatchSink creates a Logger that ships its entries to a CloudWatch Logs
+// stream, one event per entry holding the entry as a JSON object (see
+// NewNDJSONLogger for the shape) and stamped with the entry's own time.
+//
+// Entries are batched and sent when a batch reaches CloudWatch's 10,000 event or
+// 1MB limit, every few seconds, and when the Logger is closed. As CloudWatch
+// requires, each batch is sorted by time before it's sent, and split where its
+// events would span more than 24 hours, so backfilled entries (see InfoAt) and
+// clock changes don't get it rejected. Batches are sent in the background, so a
+// slow PutLogEvents call doesn't hold up logging; if CloudWatch falls far behind
+// the oldest batch is dropped and reported to the error handler. Call Close
+// before the process exits so the last batch isn't lost.
+func NewCloudWatchSink(client CloudWatchLogsClient, group, stream string) *Logger {
+	writer := &cloudWatchWriter{
+		client: client,
+		group:  group,
+		stream: stream,
+		ready:  make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	go writer.flushPeriodically(cloudWatchFlushInterval)
+	return &Logger{sink: writer, closer: writer}
+}
+
+// cloudWatchWriter is the entrySink behind a CloudWatch sink Logger.
+type cloudWatchWriter struct {
+	client CloudWatchLogsClient
+	group  string
+	stream string
+
+	sendMutex     sync.Mutex // Held while sending, so batches go out in order; guards sequenceToken
+	sequenceToken *string
+
+	mutex      sync.Mutex // Guards the fields below
+	events     []CloudWatchLogEvent
+	batchBytes int
+	full       [][]CloudWatchLogEvent // Batches waiting to be sent, oldest first
+	closed     bool
+
+	ready chan struct{} // Signals flushPeriodically that a batch is full
+	done  chan struct{} // Closed to stop flushPeriodically
+}
+
+// writeEntry implements entrySink. It queues entry as a single event, starting a
+// new batch first if entry would overflow the pending one.
+func (w *cloudWatchWriter) writeEntry(entry Entry) error {
+	message := formatJSON(entry)
+	size := len(message) + cloudWatchEventOverhead
+	timestamp := entry.Time.UnixNano() / int64(time.Millisecond)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	var err error
+	if len(w.events) > 0 &&
+		(len(w.events)+1 > cloudWatchMaxBatchEvents || w.batchBytes+size > cloudWatchMaxBatchBytes) {
+		err = w.queueBatchLocked()
+		select {
+		case w.ready <- struct{}{}:
+		default: // Already signalled
+		}
+	}
+	w.events = append(w.events, CloudWatchLogEvent{Message: message, Timestamp: timestamp})
+	w.batchBytes += size
+	return err
+}
+
+// queueBatchLocked moves the pending batch to the send queue, dropping the oldest
+// queued batch if too many are waiting. The caller must hold w.mutex.
+func (w *cloudWatchWriter) queueBatchLocked() error {
+	if len(w.events) == 0 {
+		return nil
+	}
+	var err error
+	if len(w.full) >= cloudWatchMaxQueuedBatches {
+		err = fmt.Errorf("cloudwatch: falling behind, dropped %d events", len(w.full[0]))
+		w.full = w.full[1:]
+	}
+	w.full = append(w.full, w.events)
+	w.events = nil
+	w.batchBytes = 0
+	return err
+}
+
+// Flush sends the pending batch and any queued ones, returning the first error.
+func (w *cloudWatchWriter) Flush() error {
+	w.mutex.Lock()
+	err := w.queueBatchLocked()
+	w.mutex.Unlock()
+	if sendErr := w.sendQueued(); err == nil {
+		err = sendErr
+	}
+	return err
+}
+
+// Close stops the periodic flush and sends everything pending.
+func (w *cloudWatchWriter) Close() error {
+	w.mutex.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.done)
+	}
+	w.mutex.Unlock()
+	return w.Flush()
+}
+
+// flushPeriodically sends the pending batch every interval, and full batches as
+// soon as they're queued, until Close is called.
+func (w *cloudWatchWriter) flushPeriodically(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.Flush(); err != nil {
+				reportError(err)
+			}
+		case <-w.ready:
+			if err := w.sendQueued(); err != nil {
+				reportError(err)
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// sendQueued sends the queued batches in order, returning the first error.
+func (w *cloudWatchWriter) sendQueued() error {
+	w.sendMutex.Lock()
+	defer w.sendMutex.Unlock()
+	var firstErr error
+	for {
+		w.mutex.Lock()
+		if len(w.full) == 0 {
+			w.mutex.Unlock()
+			return firstErr
+		}
+		batch := w.full[0]
+		w.full = w.full[1:]
+		w.mutex.Unlock()
+
+		for _, chunk := range cloudWatchChunks(batch) {
+			if err := w.send(chunk); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+}
+
+// cloudWatchChunks sorts batch by timestamp, keeping the order of events logged
+// at the same millisecond, and splits it where it spans more than
+// cloudWatchMaxBatchSpan.
+func cloudWatchChunks(batch []CloudWatchLogEvent) [][]CloudWatchLogEvent {
+	sort.SliceStable(batch, func(i, j int) bool { return batch[i].Timestamp < batch[j].Timestamp })
+	maxSpan := int64(cloudWatchMaxBatchSpan / time.Millisecond)
+	var chunks [][]CloudWatchLogEvent
+	start := 0
+	for i := range batch {
+		if batch[i].Timestamp-batch[start].Timestamp > maxSpan {
+			chunks = append(chunks, batch[start:i])
+			start = i
+		}
+	}
+	return append(chunks, batch[start:])
+}
+
+// send sends batch, retrying with the expected sequence token if CloudWatch
+// rejects ours. The batch is dropped on any other error so a broken client can't
+// make the queue grow without bound. The caller must hold w.sendMutex.
+func (w *cloudWatchWriter) send(batch []CloudWatchLogEvent) error {
+	for attempt := 0; ; attempt++ {
+		next, err := w.client.PutLogEvents(&PutLogEventsInput{
+			LogGroupName:  w.group,
+			LogStreamName: w.stream,
+			SequenceToken: w.sequenceToken,
+			LogEvents:     batch,
+		})
+
+		var tokenErr *InvalidSequenceTokenError
+		if errors.As(err, &tokenErr) && attempt < cloudWatchMaxTokenRetries {
+			w.sequenceToken = tokenErr.ExpectedSequenceToken
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cloudwatch: dropped %d events: %w", len(batch), err)
+		}
+		w.sequenceToken = next
+		return nil
+	}
+}
@@ -0,0 +1,279 @@
+package slog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockCloudWatchClient records every PutLogEvents call and hands out numbered sequence tokens.
+// If rejectNextToken is set, the next call fails with an InvalidSequenceTokenError.
+type mockCloudWatchClient struct {
+	mutex           sync.Mutex
+	calls           []PutLogEventsInput
+	rejectNextToken *string
+	tokensIssued    int
+}
+
+func (c *mockCloudWatchClient) PutLogEvents(input *PutLogEventsInput) (*string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Copy the events, the sink is free to reuse its slice after the call.
+	call := *input
+	call.LogEvents = append([]CloudWatchLogEvent(nil), input.LogEvents...)
+	c.calls = append(c.calls, call)
+
+	if c.rejectNextToken != nil {
+		expected := c.rejectNextToken
+		c.rejectNextToken = nil
+		return nil, &InvalidSequenceTokenError{ExpectedSequenceToken: expected}
+	}
+	c.tokensIssued++
+	token := fmt.Sprintf("token-%d", c.tokensIssued)
+	return &token, nil
+}
+
+// tokenString renders a sequence token for error messages.
+func tokenString(token *string) string {
+	if token == nil {
+		return "<nil>"
+	}
+	return *token
+}
+
+// TestCloudWatchSinkBatching ensures batches are split at the event count and byte limits,
+// carry the configured group/stream and epoch-millis timestamps, and are flushed on Close.
+func TestCloudWatchSinkBatching(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	t.Run("EventCountLimit", func(t *testing.T) {
+		client := &mockCloudWatchClient{}
+		logger := NewCloudWatchSink(client, "app-group", "app-stream")
+
+		for i := 0; i < cloudWatchMaxBatchEvents+1; i++ {
+			logger.Info("Event %d", i)
+		}
+		if err := logger.Close(); err != nil {
+			t.Fatalf("Expected Close to succeed, got %v", err)
+		}
+
+		if len(client.calls) != 2 {
+			t.Fatalf("Expected 2 PutLogEvents calls, got %d", len(client.calls))
+		}
+		if n := len(client.calls[0].LogEvents); n != cloudWatchMaxBatchEvents {
+			t.Errorf("Expected first batch to hold %d events, got %d", cloudWatchMaxBatchEvents, n)
+		}
+		if n := len(client.calls[1].LogEvents); n != 1 {
+			t.Errorf("Expected Close to flush 1 remaining event, got %d", n)
+		}
+
+		first := client.calls[0]
+		if first.LogGroupName != "app-group" || first.LogStreamName != "app-stream" {
+			t.Errorf("Expected group/stream app-group/app-stream, got %s/%s", first.LogGroupName, first.LogStreamName)
+		}
+		if !strings.Contains(first.LogEvents[0].Message, `"level":"INFO","message":"Event 0"`) {
+			t.Errorf("Expected the first event to be the JSON entry, got %q", first.LogEvents[0].Message)
+		}
+	})
+
+	t.Run("ByteLimit", func(t *testing.T) {
+		client := &mockCloudWatchClient{}
+		logger := NewCloudWatchSink(client, "app-group", "app-stream")
+
+		// Six ~200KB events don't fit in a single 1MB batch.
+		payload := strings.Repeat("x", 200*1024)
+		for i := 0; i < 6; i++ {
+			logger.Info("%s", payload)
+		}
+		logger.Close()
+
+		if len(client.calls) != 2 {
+			t.Fatalf("Expected 2 PutLogEvents calls, got %d", len(client.calls))
+		}
+		for i, call := range client.calls {
+			size := 0
+			for _, event := range call.LogEvents {
+				size += len(event.Message) + cloudWatchEventOverhead
+			}
+			if size > cloudWatchMaxBatchBytes {
+				t.Errorf("Expected batch %d to fit in %d bytes, got %d", i, cloudWatchMaxBatchBytes, size)
+			}
+		}
+		if n := len(client.calls[0].LogEvents) + len(client.calls[1].LogEvents); n != 6 {
+			t.Errorf("Expected all 6 events to be sent, got %d", n)
+		}
+	})
+}
+
+// TestCloudWatchSinkSequenceToken ensures each batch uses the token returned by the
+// previous call and that an InvalidSequenceTokenError is retried with the expected token.
+func TestCloudWatchSinkSequenceToken(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	client := &mockCloudWatchClient{}
+	logger := NewCloudWatchSink(client, "app-group", "app-stream")
+	writer := logger.closer.(*cloudWatchWriter)
+
+	logger.Info("First batch")
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Expected first flush to succeed, got %v", err)
+	}
+
+	expected := "token-from-service"
+	client.rejectNextToken = &expected
+	logger.Info("Second batch")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Expected Close to succeed after retrying the token, got %v", err)
+	}
+
+	wantTokens := []*string{nil, strPtr("token-1"), &expected}
+	if len(client.calls) != len(wantTokens) {
+		t.Fatalf("Expected %d PutLogEvents calls, got %d", len(wantTokens), len(client.calls))
+	}
+	for i, want := range wantTokens {
+		if got := client.calls[i].SequenceToken; tokenString(got) != tokenString(want) {
+			t.Errorf("Expected call %d to use token %s, got %s", i, tokenString(want), tokenString(got))
+		}
+	}
+	if msg := client.calls[2].LogEvents[0].Message; !strings.Contains(msg, `"message":"Second batch"`) {
+		t.Errorf("Expected the retried batch to be resent, got %q", msg)
+	}
+}
+
+// TestCloudWatchSinkStructuredEvents ensures each event holds the entry as JSON and
+// is stamped with the entry's time, including a backfilled one, in epoch millis.
+func TestCloudWatchSinkStructuredEvents(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetClock(nil)
+	})
+	SetGlobalMinLevel(INFO)
+	SetClock(&fakeClock{current: time.Date(2022, time.July, 4, 10, 20, 30, 0, time.UTC)})
+
+	client := &mockCloudWatchClient{}
+	logger := NewCloudWatchSink(client, "app-group", "app-stream")
+	logger.WithFields(Field{Key: "order", Value: 42}).Info("Shipped")
+	backfilled := time.Date(2022, time.July, 4, 8, 0, 0, 500*int(time.Millisecond), time.UTC)
+	logger.InfoAt(backfilled, "Imported")
+	logger.Close()
+
+	if len(client.calls) != 1 || len(client.calls[0].LogEvents) != 2 {
+		t.Fatalf("Expected 1 call with 2 events, got %+v", client.calls)
+	}
+	events := client.calls[0].LogEvents
+	if want := backfilled.UnixNano() / int64(time.Millisecond); events[0].Timestamp != want {
+		t.Errorf("Expected the backfilled timestamp %d first, got %d", want, events[0].Timestamp)
+	}
+	expected := `{"time":"2022-07-04T10:20:30Z","level":"INFO","message":"Shipped","fields":{"order":42}}`
+	if events[1].Message != expected {
+		t.Errorf("Expected %s, got %s", expected, events[1].Message)
+	}
+	if want := int64(1656930030000); events[1].Timestamp != want {
+		t.Errorf("Expected timestamp %d, got %d", want, events[1].Timestamp)
+	}
+}
+
+// TestCloudWatchSinkOutOfOrder ensures out-of-order entries are sent sorted by
+// time, and that a batch spanning more than 24 hours is split.
+func TestCloudWatchSinkOutOfOrder(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetClock(nil)
+	})
+	SetGlobalMinLevel(INFO)
+	now := time.Date(2022, time.July, 4, 10, 20, 30, 0, time.UTC)
+	SetClock(&fakeClock{current: now})
+
+	client := &mockCloudWatchClient{}
+	logger := NewCloudWatchSink(client, "app-group", "app-stream")
+	logger.Info("Third")
+	logger.InfoAt(now.Add(-time.Hour), "Second")
+	logger.InfoAt(now.Add(-48*time.Hour), "First")
+	logger.InfoAt(now, "Fourth")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Expected Close to succeed, got %v", err)
+	}
+
+	var calls [][]string
+	for _, call := range client.calls {
+		var messages []string
+		for i, event := range call.LogEvents {
+			if i > 0 && event.Timestamp < call.LogEvents[i-1].Timestamp {
+				t.Errorf("Expected events in time order, got %+v", call.LogEvents)
+			}
+			var decoded struct{ Message string }
+			json.Unmarshal([]byte(event.Message), &decoded)
+			messages = append(messages, decoded.Message)
+		}
+		calls = append(calls, messages)
+	}
+	if fmt.Sprint(calls) != "[[First] [Second Third Fourth]]" {
+		t.Errorf("Expected [[First] [Second Third Fourth]], got %v", calls)
+	}
+}
+
+// blockingCloudWatchClient holds every PutLogEvents call until release is closed.
+type blockingCloudWatchClient struct {
+	mockCloudWatchClient
+	release chan struct{}
+}
+
+func (c *blockingCloudWatchClient) PutLogEvents(input *PutLogEventsInput) (*string, error) {
+	<-c.release
+	return c.mockCloudWatchClient.PutLogEvents(input)
+}
+
+// TestCloudWatchSinkSlowClient ensures a slow PutLogEvents call doesn't hold up logging.
+func TestCloudWatchSinkSlowClient(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	client := &blockingCloudWatchClient{release: make(chan struct{})}
+	logger := NewCloudWatchSink(client, "app-group", "app-stream")
+
+	logged := make(chan struct{})
+	go func() {
+		for i := 0; i < 2*cloudWatchMaxBatchEvents+1; i++ {
+			logger.Info("Event %d", i)
+		}
+		close(logged)
+	}()
+	select {
+	case <-logged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected logging to carry on while PutLogEvents is blocked")
+	}
+
+	close(client.release)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Expected Close to succeed, got %v", err)
+	}
+	sent := 0
+	for _, call := range client.calls {
+		sent += len(call.LogEvents)
+	}
+	if sent != 2*cloudWatchMaxBatchEvents+1 {
+		t.Errorf("Expected every event to be sent, got %d", sent)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,233 @@
+package slog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ConfigAsCode renders the logger's configuration as Go statements that rebuild
+// it, for sharing an exact setup in a bug report, e.g.
+//
+//	slog.SetGlobalMinLevel(slog.DEBUG)
+//	logger := slog.NewLogger("App", nil)
+//	logger.SetTimeResolution(slog.Micros)
+//	logger.SetSensitiveFieldKeys("password", "*_secret")
+//
+// Only settings that differ from the defaults are rendered. Things that can't be
+// written as a literal, such as a sink, the function behind a WithFieldIf field, an
+// attachment's data or a field value that isn't a basic type, are rendered as
+// /* custom */.
+func (l *Logger) ConfigAsCode() string {
+	var code strings.Builder
+	line := func(format string, args ...interface{}) {
+		fmt.Fprintf(&code, format+"\n", args...)
+	}
+
+	if level := GetGlobalMinLevel(); level != INFO {
+		line("slog.SetGlobalMinLevel(%s)", levelCode(level))
+	}
+	if l.sink != nil || l.jsonLogger != nil || l.levelLoggers != nil {
+		line("logger := slog.NewLogger(%q, /* custom */ nil)", l.component)
+	} else {
+		line("logger := slog.NewLogger(%q, nil)", l.component)
+	}
+	for _, field := range l.fields {
+		if field.minLevel == allLevels { // From WithFields, so the value is fixed
+			line("logger = logger.WithFields(slog.Field{Key: %q, Value: %s})", field.key, valueCode(field.value()))
+		} else {
+			line("logger = logger.WithFieldIf(%s, %q, /* custom */ nil)", levelCode(field.minLevel), field.key)
+		}
+	}
+	for _, field := range l.meta {
+		line("logger = logger.WithMeta(%q, %s)", field.Key, valueCode(field.Value))
+	}
+	for _, a := range l.attachments {
+		line("logger = logger.WithAttachment(%q, /* custom */ nil)", a.name)
+	}
+
+	webhook := l.currentWebhook()
+	l.settingsMutex.RLock()
+	if l.timeResolution != Seconds {
+		line("logger.SetTimeResolution(%s)", timeResolutionCode(l.timeResolution))
+	}
+	if l.linePrefix != "" {
+		line("logger.SetLinePrefix(%q)", l.linePrefix)
+	}
+	if l.ghAnnotations {
+		line("logger.SetGitHubActionsAnnotations(true)")
+	}
+	if l.reportEventID {
+		line("logger.SetReportEventID(true)")
+	}
+	if l.reportFunction {
+		line("logger.SetReportFunction(true)")
+	}
+	if l.maxRecordBytes > 0 {
+		line("logger.SetMaxRecordBytes(%d)", l.maxRecordBytes)
+	}
+	if l.maxFieldValue > 0 {
+		line("logger.SetMaxFieldValueLength(%d)", l.maxFieldValue)
+	}
+	if l.maxFields > 0 {
+		line("logger.SetMaxFields(%d)", l.maxFields)
+	}
+	if l.maxAttachment > 0 {
+		line("logger.SetMaxAttachmentBytes(%d)", l.maxAttachment)
+	}
+	if l.attachmentDir != "" {
+		line("logger.SetAttachmentDir(%q)", l.attachmentDir)
+	}
+	if len(l.sampling) > 0 {
+		levels := make([]int, 0, len(l.sampling))
+		for level := range l.sampling {
+			levels = append(levels, int(level))
+		}
+		sort.Ints(levels)
+		rates := make([]string, len(levels))
+		for i, level := range levels {
+			rates[i] = fmt.Sprintf("%s: %d", levelCode(LogLevel(level)), l.sampling[LogLevel(level)])
+		}
+		line("logger.SetLevelSampling(map[slog.LogLevel]int{%s})", strings.Join(rates, ", "))
+	}
+	if l.skipEmptyMessages {
+		line("logger.SetSkipEmptyMessages(true)")
+	}
+	if l.skipEmptyMessagesWithFields {
+		line("logger.SetSkipEmptyMessagesWithFields(true)")
+	}
+	if l.closedBehavior != ReportError {
+		line("logger.SetClosedBehavior(%s)", closedBehaviorCode(l.closedBehavior))
+	}
+	if l.kvSink != nil {
+		line("logger.SetKVSink(/* custom */ nil)")
+	}
+	if l.logUnchanged {
+		line("logger.SetLogUnchanged(true)")
+	}
+	if l.exitCode != 0 {
+		line("logger.SetExitOnLevel(%s, %d)", levelCode(l.exitLevel), l.exitCode)
+	}
+	if l.summaryLevel != nil && *l.summaryLevel != INFO {
+		line("logger.SetSummaryLevel(%s)", levelCode(*l.summaryLevel))
+	}
+	if len(l.sensitiveKeys) > 0 {
+		keys := make([]string, len(l.sensitiveKeys))
+		for i, key := range l.sensitiveKeys {
+			keys[i] = fmt.Sprintf("%q", key)
+		}
+		line("logger.SetSensitiveFieldKeys(%s)", strings.Join(keys, ", "))
+	}
+	if l.stackWindow > 0 {
+		line("logger.SetFirstErrorStacks(%s)", durationCode(l.stackWindow))
+	}
+	if webhook != nil {
+		line("logger.NotifyWebhook(/* custom */ \"\", %s)", levelCode(webhook.minLevel))
+	}
+	l.settingsMutex.RUnlock()
+
+	l.fieldStackMutex.RLock()
+	for _, field := range l.fieldStack {
+		line("logger.PushField(%q, %s)", field.Key, valueCode(field.Value))
+	}
+	l.fieldStackMutex.RUnlock()
+
+	l.lastLineMutex.RLock()
+	if l.rememberLast {
+		line("logger.SetRememberLast(true)")
+	}
+	l.lastLineMutex.RUnlock()
+
+	l.suppressionsMutex.RLock()
+	for _, suppression := range l.suppressions {
+		if suppression.isRemoved() {
+			continue
+		}
+		if suppression.regex != nil {
+			line("logger.AddSuppressionRegex(regexp.MustCompile(%q))", suppression.regex.String())
+		} else {
+			line("logger.AddSuppressionPattern(%q)", suppression.substring)
+		}
+	}
+	l.suppressionsMutex.RUnlock()
+
+	return code.String()
+}
+
+// valueCode renders a field value as a Go literal if it's nil, a string, a bool,
+// a number, a LogLevel or a time.Duration, and as /* custom */ nil otherwise.
+func valueCode(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case string, bool, int, float64:
+		return fmt.Sprintf("%#v", v)
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32:
+		return fmt.Sprintf("%T(%#v)", v, v)
+	case LogLevel:
+		return levelCode(v)
+	case time.Duration:
+		return durationCode(v)
+	default:
+		return "/* custom */ nil"
+	}
+}
+
+// levelCode renders level as Go code, e.g. slog.DEBUG.
+func levelCode(level LogLevel) string {
+	if level >= ERROR && level <= FINE {
+		return "slog." + level.String()
+	}
+	return fmt.Sprintf("slog.LogLevel(%d)", int(level))
+}
+
+// timeResolutionCode renders r as Go code, e.g. slog.Micros.
+func timeResolutionCode(r TimeResolution) string {
+	switch r {
+	case Seconds:
+		return "slog.Seconds"
+	case Millis:
+		return "slog.Millis"
+	case Micros:
+		return "slog.Micros"
+	case Nanos:
+		return "slog.Nanos"
+	default:
+		return fmt.Sprintf("slog.TimeResolution(%d)", int(r))
+	}
+}
+
+// closedBehaviorCode renders behavior as Go code, e.g. slog.DropSilently.
+func closedBehaviorCode(behavior ClosedBehavior) string {
+	switch behavior {
+	case ReportError:
+		return "slog.ReportError"
+	case DropSilently:
+		return "slog.DropSilently"
+	case PanicInDebug:
+		return "slog.PanicInDebug"
+	default:
+		return fmt.Sprintf("slog.ClosedBehavior(%d)", int(behavior))
+	}
+}
+
+// durationCode renders d as Go code in the largest whole unit, e.g. 90 * time.Second.
+func durationCode(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", int64(d))
+}
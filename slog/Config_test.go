@@ -0,0 +1,92 @@
+package slog
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// TestLoggerConfigAsCode ensures a configured logger renders as the statements
+// that rebuild it, and a default one as just its constructor.
+func TestLoggerConfigAsCode(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+
+	SetGlobalMinLevel(INFO)
+	if code := NewLogger("App", nil).ConfigAsCode(); code != "logger := slog.NewLogger(\"App\", nil)\n" {
+		t.Errorf("Expected only the constructor for a default logger, got:\n%s", code)
+	}
+
+	SetGlobalMinLevel(DEBUG)
+	logger := NewLogger("App", nil).
+		WithFields(Field{Key: "region", Value: "eu-west-1"}).
+		WithFieldIf(DEBUG, "heap", func() interface{} { return 0 }).
+		WithMeta("account_id", 42).
+		WithFields(Field{Key: "cause", Value: errors.New("boom")}, Field{Key: "lazy", Value: Lazy(func() interface{} { return 1 })}).
+		WithFields(Field{Key: "ratio", Value: float32(0.5)}, Field{Key: "timeout", Value: 3 * time.Second}).
+		WithAttachment("payload", []byte{1, 2, 3})
+	logger.PushField("order", "o-1")
+	logger.SetTimeResolution(Micros)
+	logger.SetLinePrefix(">>> ")
+	logger.SetMaxRecordBytes(4096)
+	logger.SetMaxFields(50)
+	logger.SetLevelSampling(map[LogLevel]int{FINE: 100, INFO: 10})
+	logger.SetClosedBehavior(PanicInDebug)
+	logger.SetSensitiveFieldKeys("Password", "*_secret")
+	logger.SetFirstErrorStacks(90 * time.Second)
+	logger.SetRememberLast(true)
+	logger.SetKVSink(&fakeKVSink{})
+	logger.AddSuppressionPattern("healthz")
+	logger.AddSuppressionRegex(regexp.MustCompile(`^GET /static/`))
+
+	expected := `slog.SetGlobalMinLevel(slog.DEBUG)
+logger := slog.NewLogger("App", nil)
+logger = logger.WithFields(slog.Field{Key: "region", Value: "eu-west-1"})
+logger = logger.WithFieldIf(slog.DEBUG, "heap", /* custom */ nil)
+logger = logger.WithFields(slog.Field{Key: "cause", Value: /* custom */ nil})
+logger = logger.WithFields(slog.Field{Key: "lazy", Value: /* custom */ nil})
+logger = logger.WithFields(slog.Field{Key: "ratio", Value: float32(0.5)})
+logger = logger.WithFields(slog.Field{Key: "timeout", Value: 3 * time.Second})
+logger = logger.WithMeta("account_id", 42)
+logger = logger.WithAttachment("payload", /* custom */ nil)
+logger.SetTimeResolution(slog.Micros)
+logger.SetLinePrefix(">>> ")
+logger.SetMaxRecordBytes(4096)
+logger.SetMaxFields(50)
+logger.SetLevelSampling(map[slog.LogLevel]int{slog.INFO: 10, slog.FINE: 100})
+logger.SetClosedBehavior(slog.PanicInDebug)
+logger.SetKVSink(/* custom */ nil)
+logger.SetSensitiveFieldKeys("password", "*_secret")
+logger.SetFirstErrorStacks(90 * time.Second)
+logger.PushField("order", "o-1")
+logger.SetRememberLast(true)
+logger.AddSuppressionPattern("healthz")
+logger.AddSuppressionRegex(regexp.MustCompile("^GET /static/"))
+`
+	if code := logger.ConfigAsCode(); code != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, code)
+	}
+
+	sinkLogger, _ := newCaptureLogger("Sink")
+	if code := sinkLogger.ConfigAsCode(); code != "slog.SetGlobalMinLevel(slog.DEBUG)\nlogger := slog.NewLogger(\"Sink\", /* custom */ nil)\n" {
+		t.Errorf("Expected a custom output for a sink logger, got:\n%s", code)
+	}
+}
+
+// TestDurationCode ensures durations render in their largest whole unit.
+func TestDurationCode(t *testing.T) {
+	testCases := map[time.Duration]string{
+		2 * time.Hour:           "2 * time.Hour",
+		90 * time.Minute:        "90 * time.Minute",
+		1500 * time.Millisecond: "1500 * time.Millisecond",
+		time.Duration(1001):     "time.Duration(1001)",
+	}
+	for d, expected := range testCases {
+		if code := durationCode(d); code != expected {
+			t.Errorf("durationCode(%v) = %q, expected %q", d, code, expected)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package slog
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// maxDiffFields caps how many changes WithDiff records; the rest are only counted.
+const maxDiffFields = 32
+
+// diffMoreKey holds the number of changes left out of a diff beyond maxDiffFields.
+const diffMoreKey = "_more"
+
+// diffChange is one changed field in a diff. A field that was added or removed
+// has no old or no new value respectively.
+type diffChange struct {
+	old, new       interface{}
+	hasOld, hasNew bool
+}
+
+// String renders the change as old→new, with (none) for a missing side.
+func (c diffChange) String() string {
+	side := func(value interface{}, ok bool) string {
+		if !ok {
+			return "(none)"
+		}
+		return formatFieldValue(value)
+	}
+	return side(c.old, c.hasOld) + "→" + side(c.new, c.hasNew)
+}
+
+// MarshalJSON encodes the change as {"old": ..., "new": ...}, leaving out a missing side.
+func (c diffChange) MarshalJSON() ([]byte, error) {
+	object := make(map[string]interface{}, 2)
+	if c.hasOld {
+		object["old"] = c.old
+	}
+	if c.hasNew {
+		object["new"] = c.new
+	}
+	return json.Marshal(object)
+}
+
+// WithDiff returns a derived Logger whose lines carry a key field holding only
+// what changed between before and after, rather than both in full, e.g.
+//
+//	logger.WithDiff("diff", oldUser, newUser).Info("User updated")
+//
+// writes "diff={email=a@x.com→b@x.com;plan=free→pro}" in text and
+// {"email":{"old":"a@x.com","new":"b@x.com"},...} in JSON. Structs of the same type
+// are compared field by field (unexported fields are skipped, as they can't be
+// read), maps key by key (added and removed keys have only a new or an old
+// value), and pointers are followed. Any other pair is compared as a whole under
+// the "value" key. At most 32 changes are recorded; _more counts the rest.
+// See WithFieldIf for how the derived Logger relates to its parent.
+func (l *Logger) WithDiff(key string, before, after interface{}) *Logger {
+	return l.WithFields(Field{Key: key, Value: diffValues(before, after)})
+}
+
+// diffValues returns the changes between before and after, keyed by field name.
+func diffValues(before, after interface{}) map[string]interface{} {
+	diff := make(map[string]interface{})
+	more := 0
+	add := func(name string, change diffChange) {
+		if len(diff) < maxDiffFields {
+			diff[name] = change
+		} else {
+			more++
+		}
+	}
+
+	a, b := indirect(reflect.ValueOf(before)), indirect(reflect.ValueOf(after))
+	switch {
+	case a.IsValid() && b.IsValid() && a.Type() == b.Type() && a.Kind() == reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if field.PkgPath != "" { // Unexported
+				continue
+			}
+			oldValue, newValue := a.Field(i).Interface(), b.Field(i).Interface()
+			if !reflect.DeepEqual(oldValue, newValue) {
+				add(field.Name, diffChange{old: oldValue, new: newValue, hasOld: true, hasNew: true})
+			}
+		}
+	case a.IsValid() && b.IsValid() && a.Type() == b.Type() && a.Kind() == reflect.Map:
+		for _, k := range a.MapKeys() {
+			oldValue, newEntry := a.MapIndex(k).Interface(), b.MapIndex(k)
+			if !newEntry.IsValid() {
+				add(formatFieldValue(k.Interface()), diffChange{old: oldValue, hasOld: true})
+			} else if newValue := newEntry.Interface(); !reflect.DeepEqual(oldValue, newValue) {
+				add(formatFieldValue(k.Interface()), diffChange{old: oldValue, new: newValue, hasOld: true, hasNew: true})
+			}
+		}
+		for _, k := range b.MapKeys() {
+			if !a.MapIndex(k).IsValid() {
+				add(formatFieldValue(k.Interface()), diffChange{new: b.MapIndex(k).Interface(), hasNew: true})
+			}
+		}
+	default:
+		if !reflect.DeepEqual(before, after) {
+			add("value", diffChange{old: before, new: after, hasOld: true, hasNew: true})
+		}
+	}
+
+	if more > 0 {
+		diff[diffMoreKey] = more
+	}
+	return diff
+}
+
+// indirect follows pointers to the value they point at; a nil pointer gives the zero Value.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	return v
+}
@@ -0,0 +1,101 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// TestLoggerWithDiffStruct ensures only the changed exported fields of a struct
+// are attached, in both the text and JSON forms.
+func TestLoggerWithDiffStruct(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() { SetGlobalMinLevel(originalLevel) })
+	SetGlobalMinLevel(INFO)
+
+	type user struct {
+		Name   string
+		Email  string
+		Plan   string
+		secret string
+	}
+	before := user{Name: "Ada", Email: "ada@old.example", Plan: "free", secret: "a"}
+	after := &user{Name: "Ada", Email: "ada@new.example", Plan: "pro", secret: "b"}
+
+	var buffer bytes.Buffer
+	logger := newTestLogger(&buffer, "Users")
+	logger.WithDiff("diff", before, after).Info("User updated")
+
+	expected := "[INFO][Users] User updated diff={Email=ada@old.example→ada@new.example;Plan=free→pro}\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buffer.String())
+	}
+
+	encoded, err := json.Marshal(diffValues(before, after))
+	if err != nil {
+		t.Fatalf("Marshalling the diff failed: %v", err)
+	}
+	expectedJSON := `{"Email":{"new":"ada@new.example","old":"ada@old.example"},"Plan":{"new":"pro","old":"free"}}`
+	if string(encoded) != expectedJSON {
+		t.Errorf("Expected %s, got %s", expectedJSON, encoded)
+	}
+}
+
+// TestLoggerWithDiffMap ensures changed, added and removed map keys are attached,
+// each with only the sides it has.
+func TestLoggerWithDiffMap(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() { SetGlobalMinLevel(originalLevel) })
+	SetGlobalMinLevel(INFO)
+
+	before := map[string]int{"replicas": 2, "cpu": 1, "legacy": 7}
+	after := map[string]int{"replicas": 3, "cpu": 1, "memory": 512}
+
+	var buffer bytes.Buffer
+	logger := newTestLogger(&buffer, "Deploy")
+	logger.WithDiff("changes", before, after).Info("Scaled")
+
+	expected := "[INFO][Deploy] Scaled changes={legacy=7→(none);memory=(none)→512;replicas=2→3}\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buffer.String())
+	}
+
+	encoded, err := json.Marshal(diffValues(before, after))
+	if err != nil {
+		t.Fatalf("Marshalling the diff failed: %v", err)
+	}
+	expectedJSON := `{"legacy":{"old":7},"memory":{"new":512},"replicas":{"new":3,"old":2}}`
+	if string(encoded) != expectedJSON {
+		t.Errorf("Expected %s, got %s", expectedJSON, encoded)
+	}
+}
+
+// TestDiffValuesCapped ensures a diff records at most maxDiffFields changes and
+// counts the rest.
+func TestDiffValuesCapped(t *testing.T) {
+	before, after := make(map[string]int), make(map[string]int)
+	for i := 0; i < maxDiffFields+5; i++ {
+		key := fmt.Sprintf("k%02d", i)
+		before[key], after[key] = i, i+1
+	}
+
+	diff := diffValues(before, after)
+	if len(diff) != maxDiffFields+1 {
+		t.Errorf("Expected %d changes plus the count, got %d entries", maxDiffFields, len(diff))
+	}
+	if more := diff[diffMoreKey]; more != 5 {
+		t.Errorf("Expected 5 changes left out, got %v", more)
+	}
+}
+
+// TestDiffValuesScalars ensures values other than structs and maps are compared
+// as a whole.
+func TestDiffValuesScalars(t *testing.T) {
+	if diff := diffValues(1, 1); len(diff) != 0 {
+		t.Errorf("Expected no changes, got %v", diff)
+	}
+	if text := formatFieldValue(diffValues("a", 2)); text != "{value=a→2}" {
+		t.Errorf("Expected {value=a→2}, got %q", text)
+	}
+}
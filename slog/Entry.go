@@ -0,0 +1,132 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Entry is a single log record. Text output renders it as a line; sinks that
+// understand structure (e.g. journald) receive it as is.
+type Entry struct {
+	Time      time.Time
+	Level     LogLevel
+	Component string
+	Message   string
+	Fields    []Field
+	Meta      []Field // Metadata for structured outputs only, never rendered as text (see WithMeta)
+}
+
+// entrySink receives entries from a Logger in place of its text output.
+type entrySink interface {
+	writeEntry(entry Entry) error
+}
+
+// maxPooledFormatBuffer is the largest buffer formatBuffers keeps.
+const maxPooledFormatBuffer = 64 * 1024
+
+// formatBuffers recycles the buffers formatText builds lines in.
+var formatBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// formatText renders entry as "[LEVEL][COMPONENT] message key=value ...", without
+// the timestamp, applying the logger's record size cap.
+func (l *Logger) formatText(entry Entry) string {
+	line := formatBuffers.Get().(*bytes.Buffer)
+	line.Reset()
+	defer func() {
+		if line.Cap() <= maxPooledFormatBuffer { // Don't hold on to the odd huge record
+			formatBuffers.Put(line)
+		}
+	}()
+
+	// Build the prefix: [LEVEL][COMPONENT]
+	line.WriteByte('[')
+	line.WriteString(entry.Level.String())
+	line.WriteByte(']')
+	if entry.Component != "" {
+		line.WriteByte('[')
+		line.WriteString(entry.Component)
+		line.WriteByte(']')
+	}
+
+	line.WriteByte(' ')
+	line.WriteString(entry.Message)
+	for _, field := range entry.Fields {
+		fmt.Fprintf(line, " %s=%s", field.Key, formatFieldValue(field.Value))
+	}
+
+	l.settingsMutex.RLock()
+	maxRecordBytes := l.maxRecordBytes
+	l.settingsMutex.RUnlock()
+
+	record := line.String()
+	if maxRecordBytes > 0 && len(record) > maxRecordBytes {
+		record = fmt.Sprintf("%s …[record truncated, original_size=%d bytes]", truncateUTF8(record, maxRecordBytes), len(record))
+	}
+	return record
+}
+
+// jsonEntry is the JSON form of an Entry, one object per line in NDJSON output.
+type jsonEntry struct {
+	Time      string          `json:"time"`
+	Level     string          `json:"level"`
+	Component string          `json:"component,omitempty"`
+	Message   string          `json:"message"`
+	Fields    json.RawMessage `json:"fields,omitempty"`
+	Meta      json.RawMessage `json:"meta,omitempty"`
+}
+
+// formatJSON renders entry as a single-line JSON object with the time (UTC,
+// RFC 3339), level, component (if any), message, fields and meta (if any, see fieldsJSON).
+func formatJSON(entry Entry) string {
+	object := jsonEntry{
+		Time:      entry.Time.UTC().Format(time.RFC3339Nano),
+		Level:     entry.Level.String(),
+		Component: entry.Component,
+		Message:   entry.Message,
+	}
+	if len(entry.Fields) > 0 {
+		object.Fields = json.RawMessage(fieldsJSON(entry.Fields))
+	}
+	if len(entry.Meta) > 0 {
+		object.Meta = json.RawMessage(fieldsJSON(entry.Meta))
+	}
+	encoded, _ := json.Marshal(object) // Can't fail, the fields are already encoded
+	return string(encoded)
+}
+
+// formatJSONRecord renders entry with formatJSON, applying the logger's record
+// size cap: a longer record loses its fields and meta, gets a
+// record_truncated_from field with its original size, and has its message cut so
+// the record fits. The time, level and component are always kept, so a very
+// small cap can still be exceeded.
+func (l *Logger) formatJSONRecord(entry Entry) string {
+	record := formatJSON(entry)
+
+	l.settingsMutex.RLock()
+	maxRecordBytes := l.maxRecordBytes
+	l.settingsMutex.RUnlock()
+	if maxRecordBytes <= 0 || len(record) <= maxRecordBytes {
+		return record
+	}
+
+	message := entry.Message
+	entry.Fields = []Field{{Key: "record_truncated_from", Value: len(record)}}
+	entry.Meta = nil
+	entry.Message = ""
+	room := maxRecordBytes - len(formatJSON(entry))
+	for room > 0 {
+		entry.Message = truncateUTF8(message, room)
+		capped := formatJSON(entry)
+		if len(capped) <= maxRecordBytes {
+			return capped
+		}
+		room -= len(capped) - maxRecordBytes // Escaping made the message longer
+	}
+	entry.Message = ""
+	return formatJSON(entry)
+}
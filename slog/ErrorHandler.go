@@ -0,0 +1,39 @@
+package slog
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// This mutex ensures thread-safe access to the package error handler
+var errorHandlerMutex sync.RWMutex
+var errorHandler = defaultErrorHandler
+
+// defaultErrorHandler prints err to os.Stderr, which is all a logger that failed
+// to log can reasonably do.
+func defaultErrorHandler(err error) {
+	fmt.Fprintf(os.Stderr, "slog: %v\n", err)
+}
+
+// SetErrorHandler sets the function that receives the errors ALL Logger instances
+// can't return to a caller, such as a failed write, a sink dropping a batch, or a
+// line logged after Close. Passing nil restores the default, which prints them to
+// os.Stderr. The handler must not log through a Logger that reports to it.
+// It's thread-safe.
+func SetErrorHandler(handler func(err error)) {
+	errorHandlerMutex.Lock()
+	defer errorHandlerMutex.Unlock()
+	if handler == nil {
+		handler = defaultErrorHandler
+	}
+	errorHandler = handler
+}
+
+// reportError hands err to the package error handler.
+func reportError(err error) {
+	errorHandlerMutex.RLock()
+	handler := errorHandler
+	errorHandlerMutex.RUnlock()
+	handler(err)
+}
@@ -0,0 +1,60 @@
+package slog
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// errorRecorder collects the errors passed to the package error handler.
+type errorRecorder struct {
+	mutex  sync.Mutex
+	errors []error
+}
+
+func (r *errorRecorder) handle(err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.errors = append(r.errors, err)
+}
+
+func (r *errorRecorder) Errors() []error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]error(nil), r.errors...)
+}
+
+// recordErrors installs an errorRecorder as the error handler for the rest of the test.
+func recordErrors(t *testing.T) *errorRecorder {
+	recorder := &errorRecorder{}
+	SetErrorHandler(recorder.handle)
+	t.Cleanup(func() {
+		SetErrorHandler(nil)
+	})
+	return recorder
+}
+
+// failingSink rejects every entry.
+type failingSink struct{}
+
+func (failingSink) writeEntry(Entry) error {
+	return errors.New("sink unavailable")
+}
+
+// TestSetErrorHandler ensures errors from sinks reach the installed handler.
+func TestSetErrorHandler(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	recorder := recordErrors(t)
+	logger := &Logger{sink: failingSink{}}
+	logger.Info("Lost")
+
+	errs := recorder.Errors()
+	if len(errs) != 1 || errs[0].Error() != "sink unavailable" {
+		t.Errorf("Expected the sink error to be reported, got %v", errs)
+	}
+}
@@ -0,0 +1,68 @@
+package slog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// errorList is the value of a field holding several errors (see Errs).
+type errorList []error
+
+// Errs returns a Field holding errs as a list, e.g. the failures of a batch job:
+//
+//	logger.WithFields(slog.Errs("failures", errs)).Error("Import finished with errors")
+//
+// Text output renders it as a numbered list, [1) message (type); 2) ...]; structured
+// sinks receive an array of {"message", "type"} objects. nil errors are left out.
+// A field whose value is a joined error (one with an Unwrap() []error method, such
+// as the result of errors.Join) is rendered the same way.
+func Errs(key string, errs []error) Field {
+	list := make(errorList, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			list = append(list, err)
+		}
+	}
+	return Field{Key: key, Value: list}
+}
+
+// asErrorList returns value as an errorList if it holds several errors.
+func asErrorList(value interface{}) (errorList, bool) {
+	switch v := value.(type) {
+	case errorList:
+		return v, true
+	case interface{ Unwrap() []error }:
+		return Errs("", v.Unwrap()).Value.(errorList), true
+	}
+	return nil, false
+}
+
+// String renders the errors as a numbered list. An error whose Error method
+// panics is rendered as a placeholder (see printable).
+func (list errorList) String() string {
+	var text strings.Builder
+	text.WriteByte('[')
+	for i, err := range list {
+		if i > 0 {
+			text.WriteString("; ")
+		}
+		fmt.Fprintf(&text, "%d) %s (%T)", i+1, printable(err), err)
+	}
+	text.WriteByte(']')
+	return text.String()
+}
+
+// MarshalJSON encodes the errors as an array of {"message", "type"} objects,
+// with the same placeholder as String for an error whose Error method panics.
+func (list errorList) MarshalJSON() ([]byte, error) {
+	type jsonError struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	}
+	encoded := make([]jsonError, len(list))
+	for i, err := range list {
+		encoded[i] = jsonError{Message: printable(err), Type: fmt.Sprintf("%T", err)}
+	}
+	return json.Marshal(encoded)
+}
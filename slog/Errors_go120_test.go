@@ -0,0 +1,21 @@
+//go:build go1.20
+// +build go1.20
+
+package slog
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+// TestJoinedErrorField ensures the result of errors.Join is rendered as a list.
+func TestJoinedErrorField(t *testing.T) {
+	joined := errors.Join(io.ErrUnexpectedEOF, nil, fieldError{field: "id"})
+
+	text := formatFieldValue(joined)
+	expected := "[1) unexpected EOF (*errors.errorString); 2) id is required (slog.fieldError)]"
+	if text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+}
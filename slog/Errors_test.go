@@ -0,0 +1,76 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// validationErrors is an application multi-error exposing its parts via Unwrap() []error.
+type validationErrors struct {
+	errs []error
+}
+
+func (v *validationErrors) Error() string {
+	return fmt.Sprintf("%d validation errors", len(v.errs))
+}
+
+func (v *validationErrors) Unwrap() []error {
+	return v.errs
+}
+
+// fieldError is a custom error type, to check the type is reported.
+type fieldError struct {
+	field string
+}
+
+func (e fieldError) Error() string {
+	return e.field + " is required"
+}
+
+// TestErrs ensures an error list renders as a numbered list in text output and as
+// an array of message/type objects in JSON, without nil errors.
+func TestErrs(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Import")
+	errs := []error{errors.New("row 3: bad date"), nil, fieldError{field: "email"}}
+	logger.WithFields(Errs("failures", errs)).Error("Import finished with errors")
+
+	expected := "[ERROR][Import] Import finished with errors failures=[1) row 3: bad date (*errors.errorString); 2) email is required (slog.fieldError)]\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	encoded := fieldsJSON([]Field{Errs("failures", errs)})
+	expectedJSON := `{"failures":[{"message":"row 3: bad date","type":"*errors.errorString"},{"message":"email is required","type":"slog.fieldError"}]}`
+	if encoded != expectedJSON {
+		t.Errorf("Expected %s, got %s", expectedJSON, encoded)
+	}
+}
+
+// TestMultiErrorField ensures a field holding a custom multi-error is rendered as
+// a list of its parts rather than its flattened message.
+func TestMultiErrorField(t *testing.T) {
+	err := &validationErrors{errs: []error{fieldError{field: "name"}, fieldError{field: "email"}}}
+
+	text := formatFieldValue(err)
+	if text != "[1) name is required (slog.fieldError); 2) email is required (slog.fieldError)]" {
+		t.Errorf("Unexpected text rendering %q", text)
+	}
+	if encoded := fieldsJSON([]Field{{Key: "err", Value: err}}); !strings.Contains(encoded, `{"message":"email is required","type":"slog.fieldError"}`) {
+		t.Errorf("Expected the parts in the JSON rendering, got %s", encoded)
+	}
+
+	// A plain error is unaffected.
+	if text := formatFieldValue(fieldError{field: "name"}); text != "name is required" {
+		t.Errorf("Expected a plain error to render as its message, got %q", text)
+	}
+}
@@ -0,0 +1,70 @@
+package slog
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// eventIDGenerator produces UUIDv7 identifiers for log lines.
+//
+// The 12 bits following the version are used as a sequence counter within a
+// millisecond, so IDs generated by the same process sort in generation order.
+// The random bits come from math/rand: IDs are unique enough to quote in a
+// support ticket, but they are not suitable as secrets.
+type eventIDGenerator struct {
+	mutex      sync.Mutex // Guards every field below
+	lastMillis int64      // Millisecond timestamp of the last ID
+	sequence   uint16     // 12-bit counter within lastMillis
+	random     *rand.Rand
+}
+
+// eventIDs is the package-wide generator shared by all Logger instances.
+var eventIDs = &eventIDGenerator{
+	random: rand.New(rand.NewSource(time.Now().UnixNano())),
+}
+
+// next returns a new UUIDv7 in its canonical 36-character form.
+// It's thread-safe.
+func (g *eventIDGenerator) next() string {
+	g.mutex.Lock()
+	nowMillis := now().UnixNano() / int64(time.Millisecond)
+	if nowMillis > g.lastMillis {
+		g.lastMillis = nowMillis
+		// Start at a random point in the lower half so there is room to count up.
+		g.sequence = uint16(g.random.Intn(1 << 11))
+	} else {
+		// Same millisecond (or the clock went backwards): keep counting so IDs stay ordered.
+		g.sequence++
+		if g.sequence > 0x0FFF {
+			g.lastMillis++
+			g.sequence = 0
+		}
+	}
+	millis, sequence := g.lastMillis, g.sequence
+	tail := g.random.Uint64()
+	g.mutex.Unlock()
+
+	var id [16]byte
+	var millisBytes [8]byte
+	binary.BigEndian.PutUint64(millisBytes[:], uint64(millis))
+	copy(id[0:6], millisBytes[2:8])
+	id[6] = 0x70 | byte(sequence>>8)&0x0F // Version 7
+	id[7] = byte(sequence)
+	binary.BigEndian.PutUint64(id[8:], tail)
+	id[8] = id[8]&0x3F | 0x80 // RFC 4122 variant
+
+	var text [36]byte
+	hex.Encode(text[0:8], id[0:4])
+	text[8] = '-'
+	hex.Encode(text[9:13], id[4:6])
+	text[13] = '-'
+	hex.Encode(text[14:18], id[6:8])
+	text[18] = '-'
+	hex.Encode(text[19:23], id[8:10])
+	text[23] = '-'
+	hex.Encode(text[24:36], id[10:16])
+	return string(text[:])
+}
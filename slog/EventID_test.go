@@ -0,0 +1,64 @@
+package slog
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// uuidV7Pattern matches the canonical form of a UUIDv7 with the RFC 4122 variant.
+var uuidV7Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// TestLoggerReportEventID ensures every line gets a well-formed event_id that is
+// unique and increases in the order the lines were logged.
+func TestLoggerReportEventID(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Support")
+	logger.SetReportEventID(true)
+
+	numLines := 10000
+	for i := 0; i < numLines; i++ {
+		logger.Info("Line %d", i)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != numLines {
+		t.Fatalf("Expected %d log lines, got %d", numLines, len(lines))
+	}
+
+	seen := make(map[string]bool, numLines)
+	previous := ""
+	for i, line := range lines {
+		idx := strings.Index(line, " event_id=")
+		if idx < 0 {
+			t.Fatalf("Expected line %d to contain an event_id, got %q", i, line)
+		}
+		id := line[idx+len(" event_id="):]
+		if !uuidV7Pattern.MatchString(id) {
+			t.Fatalf("Expected line %d to carry a UUIDv7, got %q", i, id)
+		}
+		if seen[id] {
+			t.Fatalf("Expected unique event IDs, got duplicate %q on line %d", id, i)
+		}
+		if id <= previous {
+			t.Fatalf("Expected event IDs to increase, got %q after %q on line %d", id, previous, i)
+		}
+		seen[id] = true
+		previous = id
+	}
+
+	// Disabling it again removes the suffix.
+	buf.Reset()
+	logger.SetReportEventID(false)
+	logger.Info("No ID")
+	if output := strings.TrimSpace(buf.String()); output != "[INFO][Support] No ID" {
+		t.Errorf("Expected no event_id after SetReportEventID(false), got %q", output)
+	}
+}
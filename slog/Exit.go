@@ -0,0 +1,40 @@
+package slog
+
+import "os"
+
+// exitFunc ends the process for SetExitOnLevel; tests replace it.
+var exitFunc = os.Exit
+
+// SetExitOnLevel makes the logger exit the process with code the first time it
+// writes a line at level or more severe, e.g. SetExitOnLevel(slog.ERROR, 2) to
+// fail a CI tool on its first error. The logger, and the one owning its output if
+// it was derived, are closed first, so sinks flush their pending entries
+// including that line. A code of 0 (the default) disables it.
+// It's thread-safe.
+func (l *Logger) SetExitOnLevel(level LogLevel, code int) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.exitLevel = level
+	l.exitCode = code
+}
+
+// exitIfSevere exits the process if the written entries include one at the
+// level set by SetExitOnLevel or more severe.
+func (l *Logger) exitIfSevere(entries []Entry) {
+	l.settingsMutex.RLock()
+	exitLevel, exitCode := l.exitLevel, l.exitCode
+	l.settingsMutex.RUnlock()
+	if exitCode == 0 {
+		return
+	}
+	for _, entry := range entries {
+		if entry.Level <= exitLevel {
+			l.Close()
+			if owner := l.owner(); owner != l {
+				owner.Close() // Flushes the output, which derived loggers don't own
+			}
+			exitFunc(exitCode)
+			return
+		}
+	}
+}
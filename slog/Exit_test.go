@@ -0,0 +1,85 @@
+package slog
+
+import (
+	"io"
+	"testing"
+)
+
+// TestLoggerExitOnLevel ensures the exit func is called with the configured code
+// only for lines at or above the configured severity, after they are written.
+func TestLoggerExitOnLevel(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	originalExit := exitFunc
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		exitFunc = originalExit
+	})
+	SetGlobalMinLevel(DEBUG)
+
+	var codes []int
+	exitFunc = func(code int) {
+		codes = append(codes, code)
+	}
+
+	logger, sink := newCaptureLogger("CI")
+	logger.Error("Disabled by default")
+	if len(codes) != 0 {
+		t.Fatalf("Expected no exit before SetExitOnLevel, got %v", codes)
+	}
+
+	logger.SetExitOnLevel(WARN, 3)
+	logger.Debug("Fine")
+	logger.Info("Still fine")
+	if len(codes) != 0 {
+		t.Fatalf("Expected no exit below WARN, got %v", codes)
+	}
+
+	logger.Warn("Deprecated flag")
+	if len(codes) != 1 || codes[0] != 3 {
+		t.Fatalf("Expected exit code 3 on WARN, got %v", codes)
+	}
+	entries := sink.Entries()
+	if last := entries[len(entries)-1]; last.Message != "Deprecated flag" {
+		t.Errorf("Expected the line to be written before exiting, last entry was %q", last.Message)
+	}
+
+	// ERROR is more severe than WARN, so it exits too; derived loggers inherit the setting.
+	logger = newTestLogger(io.Discard, "CI")
+	logger.SetExitOnLevel(WARN, 4)
+	logger.WithFields(Field{Key: "step", Value: "lint"}).Error("Lint failed")
+	if len(codes) != 2 || codes[1] != 4 {
+		t.Errorf("Expected exit code 4 on ERROR from a derived logger, got %v", codes)
+	}
+}
+
+// TestLoggerExitOnLevelFlushesSink ensures a derived logger reaching the exit
+// level flushes its parent's batching sink, including the triggering line, before
+// exiting.
+func TestLoggerExitOnLevelFlushesSink(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	originalExit := exitFunc
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		exitFunc = originalExit
+	})
+	SetGlobalMinLevel(INFO)
+
+	client := &mockCloudWatchClient{}
+	var sentAtExit int
+	exitFunc = func(code int) {
+		client.mutex.Lock()
+		defer client.mutex.Unlock()
+		for _, call := range client.calls {
+			sentAtExit += len(call.LogEvents)
+		}
+	}
+
+	logger := NewCloudWatchSink(client, "ci-group", "ci-stream")
+	logger.SetExitOnLevel(ERROR, 2)
+	logger.Info("Build started")
+	logger.WithFields(Field{Key: "step", Value: "test"}).Error("Tests failed")
+
+	if sentAtExit != 2 {
+		t.Errorf("Expected both lines to be sent before exiting, got %d", sentAtExit)
+	}
+}
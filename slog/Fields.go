@@ -0,0 +1,290 @@
+package slog
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Field is a key/value pair attached to an Entry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// StrMap returns a Field holding a copy of m, for use with WithFields. Like any map
+// field, it renders with sorted keys so the output is stable.
+func StrMap(key string, m map[string]string) Field {
+	var copied map[string]string
+	if m != nil {
+		copied = make(map[string]string, len(m))
+		for k, v := range m {
+			copied[k] = v
+		}
+	}
+	return Field{Key: key, Value: copied}
+}
+
+// LazyValue is a field value computed only when the line is rendered (see Lazy).
+type LazyValue func() interface{}
+
+// Lazy wraps fn as a field value that is computed only when a line carrying it is
+// actually rendered, e.g.
+//
+//	logger.WithFields(slog.Field{Key: "state", Value: slog.Lazy(func() interface{} {
+//		return fmt.Sprintf("%+v", bigStruct)
+//	})}).Debug("Reconciling")
+//
+// so lines dropped by level filtering, sampling or suppression never pay for it.
+// fn is called once per line written, and every output renders its result.
+func Lazy(fn func() interface{}) LazyValue {
+	return LazyValue(fn)
+}
+
+// String renders the computed value like any other field value.
+func (v LazyValue) String() string {
+	return formatFieldValue(v())
+}
+
+// MarshalJSON lets structured sinks encode the computed value. If computing it
+// panics the placeholder is encoded instead, as in text output.
+func (v LazyValue) MarshalJSON() (encoded []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			reportError(fmt.Errorf("computing a lazy value panicked: %v", r))
+			encoded, err = json.Marshal(unprintable(v))
+		}
+	}()
+	return json.Marshal(v())
+}
+
+// compute calls v, substituting the placeholder and reporting to the error
+// handler if it panics, as printable does.
+func (v LazyValue) compute() (value interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			reportError(fmt.Errorf("computing a lazy value panicked: %v", r))
+			value = unprintable(v)
+		}
+	}()
+	return v()
+}
+
+// resolveLazyValues replaces each lazy value in fields with the value it
+// computes, in place, so it's computed once however many outputs render it.
+func resolveLazyValues(fields []Field) {
+	for i := range fields {
+		if lazy, ok := fields[i].Value.(LazyValue); ok {
+			fields[i].Value = lazy.compute()
+		}
+	}
+}
+
+// allLevels is the minLevel of fields that apply to lines at every level.
+const allLevels LogLevel = math.MinInt32
+
+// conditionalField is a key/value pair appended to a logger's lines.
+// Its value is only computed for lines at or finer than minLevel.
+type conditionalField struct {
+	key      string
+	minLevel LogLevel
+	value    func() interface{}
+}
+
+// WithFieldIf returns a derived Logger that appends key=value to lines logged at
+// minLevel or finer, e.g. WithFieldIf(DEBUG, ...) adds the field to DEBUG and FINE
+// lines only. fn is called once per line that includes the field and never for
+// lines that don't, so it can compute expensive debug-only context.
+//
+// The derived Logger shares the parent's output and starts with a copy of its
+// settings; changing a setting on one afterwards doesn't affect the other.
+func (l *Logger) WithFieldIf(minLevel LogLevel, key string, fn func() interface{}) *Logger {
+	derived := l.derive()
+	derived.fields = append(derived.fields, conditionalField{key: key, minLevel: minLevel, value: fn})
+	return derived
+}
+
+// WithMeta returns a derived Logger whose entries carry key=value as metadata:
+// data for routing or billing, such as an account ID, that structured outputs
+// (sinks, KVSink, JSON) receive in Entry.Meta but the human-readable text leaves
+// out. See WithFieldIf for how the derived Logger relates to its parent.
+func (l *Logger) WithMeta(key string, value interface{}) *Logger {
+	derived := l.derive()
+	derived.meta = append(derived.meta, Field{Key: key, Value: value})
+	return derived
+}
+
+// WithFields returns a derived Logger that appends the given fields to every line.
+// See WithFieldIf for how the derived Logger relates to its parent.
+func (l *Logger) WithFields(fields ...Field) *Logger {
+	derived := l.derive()
+	for _, field := range fields {
+		value := field.Value
+		derived.fields = append(derived.fields, conditionalField{
+			key:      field.Key,
+			minLevel: allLevels,
+			value:    func() interface{} { return value },
+		})
+	}
+	return derived
+}
+
+// PushField adds key=value to every subsequent line from this logger until it is
+// removed with PopField. Pushes and pops nest like a stack, in the manner of a
+// mapped diagnostic context: push when entering a scope, defer the pop when leaving.
+//
+//	logger.PushField("order", id)
+//	defer logger.PopField()
+//
+// The stack belongs to this Logger instance, not to a goroutine: fields pushed by
+// one goroutine appear on lines logged by every goroutine sharing the logger. Give
+// each concurrent task its own derived Logger (e.g. via WithFieldIf) to keep their
+// scopes apart. It's thread-safe.
+func (l *Logger) PushField(key string, value interface{}) {
+	l.fieldStackMutex.Lock()
+	defer l.fieldStackMutex.Unlock()
+	l.fieldStack = append(l.fieldStack, Field{Key: key, Value: value})
+}
+
+// PopField removes the most recently pushed field. It does nothing if the stack is empty.
+// It's thread-safe.
+func (l *Logger) PopField() {
+	l.fieldStackMutex.Lock()
+	defer l.fieldStackMutex.Unlock()
+	if len(l.fieldStack) > 0 {
+		l.fieldStack = l.fieldStack[:len(l.fieldStack)-1]
+	}
+}
+
+// derive returns a new Logger writing to the same output as l, with a copy of l's
+// fields (including those currently pushed), suppressions and settings. The output
+// stays owned by l (or whichever Logger l was derived from), so closing the derived
+// Logger doesn't close it, while closing the owner closes the derived Logger too.
+func (l *Logger) derive() *Logger {
+	l.fieldStackMutex.RLock()
+	fieldStack := append([]Field(nil), l.fieldStack...)
+	l.fieldStackMutex.RUnlock()
+
+	l.suppressionsMutex.RLock()
+	suppressions := append([]*Suppression(nil), l.suppressions...)
+	l.suppressionsMutex.RUnlock()
+
+	l.settingsMutex.RLock()
+	defer l.settingsMutex.RUnlock()
+	return &Logger{
+		root:           l.owner(),
+		internalLogger: l.internalLogger,
+		levelLoggers:   l.levelLoggers,
+		jsonLogger:     l.jsonLogger,
+		component:      l.component,
+		timestamps:     l.timestamps,
+		sink:           l.sink,
+		fields:         append([]conditionalField(nil), l.fields...),
+		attachments:    append([]*attachment(nil), l.attachments...),
+		meta:           append([]Field(nil), l.meta...),
+		fieldStack:     fieldStack,
+		suppressions:   suppressions,
+		reportEventID:  l.reportEventID,
+		reportFunction: l.reportFunction,
+		maxRecordBytes: l.maxRecordBytes,
+		maxFieldValue:  l.maxFieldValue,
+		maxFields:      l.maxFields,
+		maxAttachment:  l.maxAttachment,
+		attachmentDir:  l.attachmentDir,
+		sampling:       l.sampling,
+		timeResolution: l.timeResolution,
+		closedBehavior: l.closedBehavior,
+		kvSink:         l.kvSink,
+		logUnchanged:   l.logUnchanged,
+		exitLevel:      l.exitLevel,
+		exitCode:       l.exitCode,
+		summaryLevel:   l.summaryLevel,
+		sensitiveKeys:  l.sensitiveKeys,
+		stackWindow:    l.stackWindow,
+		linePrefix:     l.linePrefix,
+		ghAnnotations:  l.ghAnnotations,
+
+		skipEmptyMessages:           l.skipEmptyMessages,
+		skipEmptyMessagesWithFields: l.skipEmptyMessagesWithFields,
+	}
+}
+
+// entryFields evaluates the fields that apply at level, followed by the pushed
+// fields and the calling goroutine's request_id.
+func (l *Logger) entryFields(level LogLevel) []Field {
+	var fields []Field
+	for _, field := range l.fields {
+		if level < field.minLevel {
+			continue
+		}
+		fields = append(fields, Field{Key: field.key, Value: field.value()})
+	}
+
+	l.fieldStackMutex.RLock()
+	fields = append(fields, l.fieldStack...)
+	l.fieldStackMutex.RUnlock()
+
+	if requestID, ok := requestIDs.get(); ok {
+		fields = append(fields, Field{Key: "request_id", Value: requestID})
+	}
+	return fields
+}
+
+// formatFieldValue renders a field value for text output. Maps are rendered as
+// {k1=v1;k2=v2} with their keys sorted, so the output is stable across runs; a nil
+// map renders as <nil> and an empty one as {}. Lazy values are computed first, and
+// joined errors are rendered as a list (see Errs). Other values use their %v form,
+// or a placeholder if formatting them panics.
+func formatFieldValue(value interface{}) (rendered string) {
+	if lazy, ok := value.(LazyValue); ok {
+		defer func() {
+			if r := recover(); r != nil {
+				reportError(fmt.Errorf("computing a lazy value panicked: %v", r))
+				rendered = unprintable(lazy)
+			}
+		}()
+		value = lazy()
+	}
+	if list, ok := asErrorList(value); ok {
+		return list.String()
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Map {
+		return printable(value)
+	}
+	if v.IsNil() {
+		return "<nil>"
+	}
+
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return lessMapKey(keys[i], keys[j])
+	})
+	var text strings.Builder
+	text.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			text.WriteByte(';')
+		}
+		fmt.Fprintf(&text, "%s=%s", formatFieldValue(key.Interface()), formatFieldValue(v.MapIndex(key).Interface()))
+	}
+	text.WriteByte('}')
+	return text.String()
+}
+
+// lessMapKey orders map keys: numerically for numbers, by their text otherwise.
+func lessMapKey(a, b reflect.Value) bool {
+	switch {
+	case a.Kind() >= reflect.Int && a.Kind() <= reflect.Int64 && a.Kind() == b.Kind():
+		return a.Int() < b.Int()
+	case a.Kind() >= reflect.Uint && a.Kind() <= reflect.Uintptr && a.Kind() == b.Kind():
+		return a.Uint() < b.Uint()
+	case (a.Kind() == reflect.Float32 || a.Kind() == reflect.Float64) && a.Kind() == b.Kind():
+		return a.Float() < b.Float()
+	default:
+		return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+	}
+}
@@ -0,0 +1,261 @@
+package slog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestLoggerWithFieldIf ensures a conditional field is only included, and its value
+// function only called, for lines at or finer than its minimum level.
+func TestLoggerWithFieldIf(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(FINE)
+
+	var buf bytes.Buffer
+	parent := newTestLogger(&buf, "HTTP")
+	calls := 0
+	logger := parent.WithFieldIf(DEBUG, "request", func() interface{} {
+		calls++
+		return "GET /orders"
+	})
+
+	testCases := []struct {
+		level       LogLevel
+		log         func(msg string, params ...interface{})
+		expectField bool
+	}{
+		{ERROR, logger.Error, false},
+		{WARN, logger.Warn, false},
+		{INFO, logger.Info, false},
+		{DEBUG, logger.Debug, true},
+		{FINE, logger.Fine, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.level.String(), func(t *testing.T) {
+			buf.Reset()
+			calls = 0
+
+			tc.log("Handled")
+
+			output := strings.TrimSpace(buf.String())
+			expected := "[" + tc.level.String() + "][HTTP] Handled"
+			expectedCalls := 0
+			if tc.expectField {
+				expected += " request=GET /orders"
+				expectedCalls = 1
+			}
+			if output != expected {
+				t.Errorf("Expected %q, got %q", expected, output)
+			}
+			if calls != expectedCalls {
+				t.Errorf("Expected the value function to be called %d times, got %d", expectedCalls, calls)
+			}
+		})
+	}
+
+	t.Run("FilteredLine", func(t *testing.T) {
+		SetGlobalMinLevel(INFO)
+		defer SetGlobalMinLevel(FINE)
+		buf.Reset()
+		calls = 0
+
+		logger.Debug("Filtered")
+		if calls != 0 {
+			t.Errorf("Expected the value function not to be called for a filtered line, got %d calls", calls)
+		}
+	})
+
+	t.Run("ParentUnchanged", func(t *testing.T) {
+		buf.Reset()
+		parent.Debug("From parent")
+		if output := strings.TrimSpace(buf.String()); output != "[DEBUG][HTTP] From parent" {
+			t.Errorf("Expected the parent logger to have no fields, got %q", output)
+		}
+	})
+}
+
+// TestLoggerPushPopField ensures pushed fields appear in push order on subsequent
+// lines and vanish once popped.
+func TestLoggerPushPopField(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "MDC")
+
+	handleItem := func(item int) {
+		logger.PushField("item", item)
+		defer logger.PopField()
+		logger.Info("Processing item")
+	}
+	handleOrder := func(order string) {
+		logger.PushField("order", order)
+		defer logger.PopField()
+		logger.Info("Start order")
+		handleItem(1)
+		handleItem(2)
+		logger.Info("End order")
+	}
+
+	logger.Info("Before")
+	handleOrder("A-1")
+	logger.Info("After")
+	logger.PopField() // Popping an empty stack is a no-op
+
+	expected := []string{
+		"[INFO][MDC] Before",
+		"[INFO][MDC] Start order order=A-1",
+		"[INFO][MDC] Processing item order=A-1 item=1",
+		"[INFO][MDC] Processing item order=A-1 item=2",
+		"[INFO][MDC] End order order=A-1",
+		"[INFO][MDC] After",
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected lines:\n%s\nGot:\n%s", strings.Join(expected, "\n"), strings.Join(lines, "\n"))
+	}
+}
+
+// TestLoggerMapFieldsDeterministic ensures map fields render with sorted keys, so the
+// same map always produces byte-identical output, and that nil and empty maps differ.
+func TestLoggerMapFieldsDeterministic(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	labels := map[string]string{"zone": "eu-1", "app": "shop", "tier": "web", "env": "prod", "build": "42"}
+	counts := map[int]int{10: 1, 9: 2, 100: 3}
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Maps").WithFields(
+		StrMap("labels", labels),
+		Field{Key: "counts", Value: counts},
+		StrMap("none", nil),
+		StrMap("empty", map[string]string{}),
+	)
+
+	logger.Info("Deployed")
+	first := buf.String()
+	buf.Reset()
+	logger.Info("Deployed")
+	second := buf.String()
+
+	if first != second {
+		t.Errorf("Expected byte-identical output, got:\n%q\n%q", first, second)
+	}
+	expected := "[INFO][Maps] Deployed labels={app=shop;build=42;env=prod;tier=web;zone=eu-1} counts={9=2;10=1;100=3} none=<nil> empty={}"
+	if output := strings.TrimSpace(first); output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+
+	// StrMap copies the map, so later changes don't show up in the output.
+	labels["zone"] = "us-1"
+	buf.Reset()
+	logger.Info("Deployed")
+	if buf.String() != first {
+		t.Errorf("Expected StrMap to be unaffected by changes to the original map, got %q", buf.String())
+	}
+}
+
+// TestFieldsJSONMaps ensures structured output renders maps as JSON objects with
+// sorted keys, and nil and empty maps distinctly.
+func TestFieldsJSONMaps(t *testing.T) {
+	fields := []Field{
+		StrMap("labels", map[string]string{"b": "2", "a": "1"}),
+		StrMap("none", nil),
+		StrMap("empty", map[string]string{}),
+	}
+	expected := `{"empty":{},"labels":{"a":"1","b":"2"},"none":null}`
+	for i := 0; i < 3; i++ {
+		if encoded := fieldsJSON(fields); encoded != expected {
+			t.Fatalf("Expected %s, got %s", expected, encoded)
+		}
+	}
+}
+
+// TestLazyFieldValue ensures a lazy value is only computed for lines that are
+// written, and renders like the value it computes.
+func TestLazyFieldValue(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	calls := 0
+	state := Lazy(func() interface{} {
+		calls++
+		return map[string]int{"b": 2, "a": 1}
+	})
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Sync").WithFields(Field{Key: "state", Value: state})
+	logger.Debug("Filtered out")
+	logger.AddSuppressionPattern("noisy")
+	logger.Info("noisy line")
+	if calls != 0 {
+		t.Fatalf("Expected the lazy value not to be computed for dropped lines, got %d calls", calls)
+	}
+
+	logger.Info("Reconciling")
+	if calls != 1 {
+		t.Errorf("Expected the lazy value to be computed once for a written line, got %d calls", calls)
+	}
+	if expected := "[INFO][Sync] Reconciling state={a=1;b=2}\n"; buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	encoded := fieldsJSON([]Field{{Key: "state", Value: state}})
+	if encoded != `{"state":{"a":1,"b":2}}` {
+		t.Errorf("Expected the lazy value to encode as JSON, got %s", encoded)
+	}
+}
+
+// TestLazyFieldValueComputedOnce ensures a lazy value is computed once per line
+// however many outputs render it, and with a field value cap set.
+func TestLazyFieldValueComputedOnce(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	calls := 0
+	state := Lazy(func() interface{} {
+		calls++
+		return "synced"
+	})
+
+	var stdout, stderr bytes.Buffer
+	ndjson := newNDJSONLogger("Sync", &stdout, &stderr, true)
+	ndjson.SetMaxFieldValueLength(100)
+	ndjson.SetRememberLast(true)
+	ndjson.WithFields(Field{Key: "state", Value: state}).Info("Reconciling")
+	if calls != 1 {
+		t.Errorf("Expected one call for the text and JSON outputs, got %d", calls)
+	}
+	if !strings.Contains(stdout.String(), `"state":"synced"`) || !strings.Contains(stderr.String(), "state=synced") {
+		t.Errorf("Expected both outputs to render the value, got %q and %q", stdout.String(), stderr.String())
+	}
+
+	calls = 0
+	logger, sink := newCaptureLogger("Sync")
+	logger.SetMaxFieldValueLength(100)
+	logger.WithFields(Field{Key: "state", Value: state}).Info("Reconciling")
+	if calls != 1 {
+		t.Errorf("Expected one call for a sink, got %d", calls)
+	}
+	if entries := sink.Entries(); len(entries) != 1 || entries[0].Fields[0].Value != "synced" {
+		t.Errorf("Expected the sink to receive the computed value, got %v", entries)
+	}
+}
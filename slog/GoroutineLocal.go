@@ -0,0 +1,124 @@
+package slog
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// goroutineLocal maps goroutine IDs to a string value, giving each goroutine its own slot.
+//
+// Go deliberately has no goroutine-local storage, and goroutines are reused by
+// worker pools, so a value set by one task stays visible to the next task run on
+// the same goroutine until it is cleared.
+type goroutineLocal struct {
+	size   int32 // len(values), read atomically so lookups are free while nothing is set
+	mutex  sync.RWMutex
+	values map[uint64]string
+}
+
+// set stores value for the calling goroutine.
+func (g *goroutineLocal) set(value string) {
+	id := goroutineID()
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if g.values == nil {
+		g.values = make(map[uint64]string)
+	}
+	g.values[id] = value
+	atomic.StoreInt32(&g.size, int32(len(g.values)))
+}
+
+// clear removes the calling goroutine's value.
+func (g *goroutineLocal) clear() {
+	id := goroutineID()
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	delete(g.values, id)
+	atomic.StoreInt32(&g.size, int32(len(g.values)))
+}
+
+// get returns the calling goroutine's value, if it has one.
+func (g *goroutineLocal) get() (string, bool) {
+	if atomic.LoadInt32(&g.size) == 0 {
+		return "", false
+	}
+	id := goroutineID()
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+	value, ok := g.values[id]
+	return value, ok
+}
+
+// goroutineID returns the calling goroutine's ID, parsed from the header of its
+// stack trace ("goroutine 42 [running]:").
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := strings.TrimPrefix(string(buf[:runtime.Stack(buf[:], false)]), "goroutine ")
+	if end := strings.IndexByte(header, ' '); end >= 0 {
+		header = header[:end]
+	}
+	id, _ := strconv.ParseUint(header, 10, 64)
+	return id
+}
+
+// --- Request IDs ---
+
+// requestIDs holds the request ID set by each goroutine.
+var requestIDs goroutineLocal
+
+// SetRequestID sets the request ID for the calling goroutine. Until ClearRequestID
+// is called, every line logged from this goroutine, by any Logger, carries a
+// request_id field, without threading a context through every function.
+//
+// The ID is tied to the goroutine, not the request: goroutines started by the
+// request don't inherit it, and a pooled goroutine keeps it for its next task.
+// Always pair it with a deferred ClearRequestID:
+//
+//	slog.SetRequestID(id)
+//	defer slog.ClearRequestID()
+//
+// It's thread-safe.
+func SetRequestID(id string) {
+	requestIDs.set(id)
+}
+
+// ClearRequestID removes the calling goroutine's request ID.
+// It's thread-safe.
+func ClearRequestID() {
+	requestIDs.clear()
+}
+
+// --- Goroutine Components ---
+
+// goroutineComponents holds the component override set by each goroutine.
+var goroutineComponents goroutineLocal
+
+// SetGoroutineComponent makes every line logged from the calling goroutine, by
+// any Logger, use name as its component instead of the Logger's own, until
+// ClearGoroutineComponent is called. It suits fixed worker goroutines that tag
+// their lines with their ID once at startup:
+//
+//	go func(id int) {
+//		slog.SetGoroutineComponent(fmt.Sprintf("worker-%d", id))
+//		defer slog.ClearGoroutineComponent()
+//		for job := range jobs {
+//			...
+//		}
+//	}(i)
+//
+// Like SetRequestID, the override is tied to the goroutine: goroutines it starts
+// don't inherit it, and a goroutine reused by a pool keeps it for its next task,
+// so clear it when the goroutine's work is done.
+// It's thread-safe.
+func SetGoroutineComponent(name string) {
+	goroutineComponents.set(name)
+}
+
+// ClearGoroutineComponent removes the calling goroutine's component override.
+// It's thread-safe.
+func ClearGoroutineComponent() {
+	goroutineComponents.clear()
+}
@@ -0,0 +1,141 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestRequestID ensures the request_id field appears on lines from the goroutine that
+// set it, disappears once cleared, and never shows up on other goroutines' lines.
+func TestRequestID(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "API")
+
+	SetRequestID("req-123")
+	logger.Info("Handling")
+	if output := strings.TrimSpace(buf.String()); output != "[INFO][API] Handling request_id=req-123" {
+		t.Errorf("Expected the request_id field, got %q", output)
+	}
+
+	// Another goroutine doesn't see this goroutine's ID.
+	buf.Reset()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Info("Background")
+	}()
+	wg.Wait()
+	if output := strings.TrimSpace(buf.String()); output != "[INFO][API] Background" {
+		t.Errorf("Expected no request_id on another goroutine, got %q", output)
+	}
+
+	buf.Reset()
+	ClearRequestID()
+	logger.Info("Done")
+	if output := strings.TrimSpace(buf.String()); output != "[INFO][API] Done" {
+		t.Errorf("Expected no request_id after ClearRequestID, got %q", output)
+	}
+}
+
+// TestRequestIDConcurrent ensures concurrent goroutines each log their own request ID.
+func TestRequestIDConcurrent(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	numGoroutines := 50
+	outputs := make([]bytes.Buffer, numGoroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			logger := newTestLogger(&outputs[g], "")
+			id := strings.Repeat("x", g+1)
+			SetRequestID(id)
+			defer ClearRequestID()
+			for j := 0; j < 10; j++ {
+				logger.Info("Step")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for g := range outputs {
+		expected := "[INFO] Step request_id=" + strings.Repeat("x", g+1)
+		for _, line := range strings.Split(strings.TrimSpace(outputs[g].String()), "\n") {
+			if line != expected {
+				t.Fatalf("Expected goroutine %d to log %q, got %q", g, expected, line)
+			}
+		}
+	}
+	if size := len(requestIDs.values); size != 0 {
+		t.Errorf("Expected every request ID to be cleared, %d remain", size)
+	}
+}
+
+// TestGoroutineComponent ensures each worker goroutine's lines carry its own
+// component, lines from other goroutines keep the Logger's, and the override
+// goes away once cleared.
+func TestGoroutineComponent(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	logger, sink := newCaptureLogger("Pool")
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			SetGoroutineComponent(fmt.Sprintf("worker-%d", id))
+			defer ClearGoroutineComponent()
+			logger.Info("job %d", id)
+		}(i)
+	}
+	wg.Wait()
+	logger.Info("Pool drained")
+
+	entries := sink.Entries()
+	if len(entries) != workers+1 {
+		t.Fatalf("Expected %d entries, got %d", workers+1, len(entries))
+	}
+	for _, entry := range entries[:workers] {
+		var id int
+		fmt.Sscanf(entry.Message, "job %d", &id)
+		if expected := fmt.Sprintf("worker-%d", id); entry.Component != expected {
+			t.Errorf("Expected %q from %s, got %q", entry.Message, expected, entry.Component)
+		}
+	}
+	if component := entries[workers].Component; component != "Pool" {
+		t.Errorf("Expected the Logger's component on another goroutine, got %q", component)
+	}
+
+	SetGoroutineComponent("main")
+	logger.Info("Overridden")
+	ClearGoroutineComponent()
+	logger.Info("Restored")
+	entries = sink.Entries()
+	if entries[workers+1].Component != "main" || entries[workers+2].Component != "Pool" {
+		t.Errorf("Expected the override until cleared, got %q then %q", entries[workers+1].Component, entries[workers+2].Component)
+	}
+	if goroutineComponents.size != 0 {
+		t.Errorf("Expected no overrides left, got %d", goroutineComponents.size)
+	}
+}
@@ -0,0 +1,60 @@
+package slog
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"syscall"
+)
+
+// journaldFileDirs are where oversized entries are written, in order of
+// preference. journald only accepts files passed from these directories.
+var journaldFileDirs = []string{"/dev/shm", "/tmp"}
+
+// sendJournaldDatagram sends datagram to journald. If it's too large for the
+// socket it's written to an unlinked temporary file instead, and the file's
+// descriptor is sent in its place.
+func sendJournaldDatagram(conn *net.UnixConn, datagram []byte) error {
+	_, err := conn.Write(datagram)
+	if !errors.Is(err, syscall.EMSGSIZE) && !errors.Is(err, syscall.ENOBUFS) {
+		return err
+	}
+
+	file, err := createJournaldFile()
+	if err != nil {
+		return fmt.Errorf("journald: entry too large for a datagram: %w", err)
+	}
+	defer file.Close()
+	os.Remove(file.Name()) // The descriptor keeps the contents alive
+	if _, err := file.Write(datagram); err != nil {
+		return fmt.Errorf("journald: entry too large for a datagram: %w", err)
+	}
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var sendErr error
+	err = raw.Write(func(fd uintptr) bool {
+		// WriteMsgUnix refuses connected datagram sockets, so send the descriptor directly.
+		sendErr = syscall.Sendmsg(int(fd), nil, syscall.UnixRights(int(file.Fd())), nil, 0)
+		return sendErr != syscall.EAGAIN
+	})
+	if err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// createJournaldFile creates a temporary file in the first usable journaldFileDirs entry.
+func createJournaldFile() (*os.File, error) {
+	var err error
+	for _, dir := range journaldFileDirs {
+		var file *os.File
+		if file, err = ioutil.TempFile(dir, "slog-journal-"); err == nil {
+			return file, nil
+		}
+	}
+	return nil, err
+}
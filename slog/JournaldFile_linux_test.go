@@ -0,0 +1,71 @@
+package slog
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestJournaldSinkLargeEntry ensures an entry too large for a datagram is passed
+// to journald in a file, whose descriptor arrives in place of the datagram.
+func TestJournaldSinkLargeEntry(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	path := filepath.Join(t.TempDir(), "journal.socket")
+	stub, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("Failed to create stub journald socket: %v", err)
+	}
+	defer stub.Close()
+
+	logger, err := newJournaldSink(path)
+	if err != nil {
+		t.Fatalf("Expected to connect to the stub socket, got %v", err)
+	}
+	defer logger.Close()
+	errs := recordErrors(t)
+
+	message := strings.Repeat("x", 4*1024*1024)
+	logger.Info("%s", message)
+	if reported := errs.Errors(); len(reported) != 0 {
+		t.Fatalf("Expected the large entry to be sent, got %v", reported)
+	}
+
+	buf, oob := make([]byte, 1024), make([]byte, syscall.CmsgSpace(4))
+	stub.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, oobn, _, _, err := stub.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatalf("Expected a message on the stub socket, got %v", err)
+	}
+	if n != 0 {
+		t.Errorf("Expected an empty datagram carrying a descriptor, got %d bytes", n)
+	}
+	messages, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("Expected one control message, got %v (%v)", messages, err)
+	}
+	fds, err := syscall.ParseUnixRights(&messages[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("Expected one descriptor, got %v (%v)", fds, err)
+	}
+	file := os.NewFile(uintptr(fds[0]), "journal")
+	defer file.Close()
+	file.Seek(0, 0)
+	contents, err := ioutil.ReadAll(file)
+	if err != nil {
+		t.Fatalf("Reading the passed file failed: %v", err)
+	}
+	if expected := "MESSAGE=" + message + "\nPRIORITY=6\n"; !bytes.Equal(contents, []byte(expected)) {
+		t.Errorf("Expected the file to hold the %d byte entry, got %d bytes", len(expected), len(contents))
+	}
+}
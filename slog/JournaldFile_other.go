@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package slog
+
+import "net"
+
+// sendJournaldDatagram sends datagram to journald. Oversized entries fail, as
+// passing them in a file needs Linux.
+func sendJournaldDatagram(conn *net.UnixConn, datagram []byte) error {
+	_, err := conn.Write(datagram)
+	return err
+}
@@ -0,0 +1,127 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// journaldSocket is where journald listens for its native protocol.
+const journaldSocket = "/run/systemd/journal/socket"
+
+// journaldPriorities maps our levels to syslog priorities for the PRIORITY field.
+var journaldPriorities = map[LogLevel]int{
+	ERROR: 3, // err
+	WARN:  4, // warning
+	INFO:  6, // info
+	DEBUG: 7, // debug
+	FINE:  7, // debug
+}
+
+// NewJournaldSink creates a Logger that sends entries to journald using its native
+// protocol, so they can be queried with e.g. `journalctl PRIORITY=3`.
+//
+// The message becomes MESSAGE, the level becomes PRIORITY, the component becomes
+// COMPONENT, and each field, then each metadata field (see WithMeta), becomes a
+// journal field with an uppercased name. A field whose name would clash with one
+// of those three, such as a "message" field, is prefixed with FIELD_.
+// Entries too large for a datagram (around 200KB by default) are passed to
+// journald in a temporary file, as sd_journal_send does, on Linux; elsewhere
+// they're reported to the error handler.
+// If journald isn't running an error is returned, so the caller can fall back:
+//
+//	logger, err := slog.NewJournaldSink()
+//	if err != nil {
+//		logger = slog.NewLogger("", nil)
+//	}
+func NewJournaldSink() (*Logger, error) {
+	return newJournaldSink(journaldSocket)
+}
+
+// newJournaldSink creates a journald sink Logger that sends to the socket at path.
+func newJournaldSink(path string) (*Logger, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("journald not available: %w", err)
+	}
+	sink := &journaldWriter{conn: conn}
+	return &Logger{sink: sink, closer: sink}, nil
+}
+
+// journaldWriter sends each entry to journald as a single datagram.
+type journaldWriter struct {
+	conn *net.UnixConn
+}
+
+// writeEntry implements entrySink.
+func (w *journaldWriter) writeEntry(entry Entry) error {
+	var datagram bytes.Buffer
+	writeJournaldField(&datagram, "MESSAGE", entry.Message)
+	priority, ok := journaldPriorities[entry.Level]
+	if !ok {
+		priority = journaldPriorities[INFO]
+	}
+	writeJournaldField(&datagram, "PRIORITY", fmt.Sprint(priority))
+	if entry.Component != "" {
+		writeJournaldField(&datagram, "COMPONENT", entry.Component)
+	}
+	for _, field := range entry.Fields {
+		writeJournaldField(&datagram, journaldFieldName(field.Key), formatFieldValue(field.Value))
+	}
+	for _, field := range entry.Meta {
+		writeJournaldField(&datagram, journaldFieldName(field.Key), formatFieldValue(field.Value))
+	}
+
+	return sendJournaldDatagram(w.conn, datagram.Bytes())
+}
+
+// Close closes the connection to journald.
+func (w *journaldWriter) Close() error {
+	return w.conn.Close()
+}
+
+// writeJournaldField appends one field in journald's native format: NAME=value
+// for single-line values, or NAME, a little-endian 64-bit length and the raw value
+// for values containing a newline.
+func writeJournaldField(buf *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		fmt.Fprintf(buf, "%s=%s\n", name, value)
+		return
+	}
+	buf.WriteString(name)
+	buf.WriteByte('\n')
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	buf.Write(size[:])
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journaldReservedNames are the journal fields the sink writes itself.
+var journaldReservedNames = map[string]bool{"MESSAGE": true, "PRIORITY": true, "COMPONENT": true}
+
+// journaldFieldName converts a field key to a valid journal field name: at most
+// 64 uppercase letters, digits and underscores, not starting with an underscore
+// (reserved for trusted fields) or a digit, and not one of journaldReservedNames.
+func journaldFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+	name = strings.TrimLeft(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || journaldReservedNames[name] {
+		name = "FIELD_" + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
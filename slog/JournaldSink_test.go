@@ -0,0 +1,111 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestJournaldSinkWireFormat ensures entries reach the socket in journald's native
+// format, with levels mapped to PRIORITY and fields to uppercase journal fields.
+func TestJournaldSinkWireFormat(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(FINE)
+
+	path := filepath.Join(t.TempDir(), "journal.socket")
+	stub, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("Failed to create stub journald socket: %v", err)
+	}
+	defer stub.Close()
+
+	sink, err := newJournaldSink(path)
+	if err != nil {
+		t.Fatalf("Expected to connect to the stub socket, got %v", err)
+	}
+	defer sink.Close()
+	sink.component = "Billing"
+	logger := sink.WithFieldIf(ERROR, "order-id", func() interface{} { return 42 }).
+		WithFieldIf(ERROR, "trace", func() interface{} { return "line one\nline two" }).
+		WithMeta("account_id", "acct-9")
+
+	receive := func() []byte {
+		buf := make([]byte, 65536)
+		stub.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := stub.ReadFromUnix(buf)
+		if err != nil {
+			t.Fatalf("Expected a datagram on the stub socket, got %v", err)
+		}
+		return buf[:n]
+	}
+
+	logger.Error("Charge %s failed", "ch_1")
+
+	var expected bytes.Buffer
+	expected.WriteString("MESSAGE=Charge ch_1 failed\nPRIORITY=3\nCOMPONENT=Billing\nORDER_ID=42\n")
+	expected.WriteString("TRACE\n")
+	binary.Write(&expected, binary.LittleEndian, uint64(len("line one\nline two")))
+	expected.WriteString("line one\nline two\n")
+	expected.WriteString("ACCOUNT_ID=acct-9\n")
+	if got := receive(); !bytes.Equal(got, expected.Bytes()) {
+		t.Errorf("Expected datagram:\n%q\nGot:\n%q", expected.Bytes(), got)
+	}
+
+	// Each level maps to its priority.
+	priorities := []struct {
+		log      func(msg string, params ...interface{})
+		expected string
+	}{
+		{sink.Warn, "MESSAGE=m\nPRIORITY=4\nCOMPONENT=Billing\n"},
+		{sink.Info, "MESSAGE=m\nPRIORITY=6\nCOMPONENT=Billing\n"},
+		{sink.Debug, "MESSAGE=m\nPRIORITY=7\nCOMPONENT=Billing\n"},
+		{sink.Fine, "MESSAGE=m\nPRIORITY=7\nCOMPONENT=Billing\n"},
+	}
+	for _, tc := range priorities {
+		tc.log("m")
+		if got := string(receive()); got != tc.expected {
+			t.Errorf("Expected datagram %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+// TestJournaldSinkUnavailable ensures a missing journald socket is reported as an
+// error rather than a broken Logger.
+func TestJournaldSinkUnavailable(t *testing.T) {
+	logger, err := newJournaldSink(filepath.Join(t.TempDir(), "missing.socket"))
+	if err == nil {
+		t.Fatalf("Expected an error for a missing socket, got a logger %+v", logger)
+	}
+}
+
+// TestJournaldFieldName ensures keys are converted to valid journal field names.
+func TestJournaldFieldName(t *testing.T) {
+	testCases := []struct {
+		key      string
+		expected string
+	}{
+		{"user", "USER"},
+		{"request.id", "REQUEST_ID"},
+		{"_private", "PRIVATE"},
+		{"2fa", "FIELD_2FA"},
+		{"", "FIELD_"},
+		{"message", "FIELD_MESSAGE"},
+		{"Priority", "FIELD_PRIORITY"},
+		{"component", "FIELD_COMPONENT"},
+		{"message_id", "MESSAGE_ID"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			if name := journaldFieldName(tc.key); name != tc.expected {
+				t.Errorf("Expected %q to become %q, got %q", tc.key, tc.expected, name)
+			}
+		})
+	}
+}
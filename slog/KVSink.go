@@ -0,0 +1,242 @@
+package slog
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KVSink stores entries in a key-value store, grouped by key.
+type KVSink interface {
+	Store(key string, entry Entry) error
+}
+
+// SetKVSink makes the logger also store each entry that has a request_id field
+// (see SetRequestID) in sink, keyed by the request ID, so all the lines for a
+// request can be fetched together. Entries without a request ID are only written
+// to the normal output. Store errors go to the error handler. Passing nil stops
+// storing entries.
+// It's thread-safe.
+func (l *Logger) SetKVSink(sink KVSink) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.kvSink = sink
+}
+
+// storeEntries hands the entries with a request ID to the logger's KVSink, if any.
+func (l *Logger) storeEntries(entries []Entry) {
+	l.settingsMutex.RLock()
+	sink := l.kvSink
+	l.settingsMutex.RUnlock()
+	if sink == nil {
+		return
+	}
+	for _, entry := range entries {
+		for _, field := range entry.Fields {
+			if field.Key != "request_id" {
+				continue
+			}
+			if err := sink.Store(formatFieldValue(field.Value), entry); err != nil {
+				reportError(err)
+			}
+			break
+		}
+	}
+}
+
+// redisQueueSize is how many entries can wait to be sent before Store drops them.
+const redisQueueSize = 1024
+
+// redisTimeout bounds connecting to Redis and each command exchange. It's a
+// variable so tests can shorten it.
+var redisTimeout = 2 * time.Second
+
+// RedisKVSink is a KVSink that appends each entry, as a JSON object, to a Redis
+// list named after its key, e.g. `LRANGE req-42 0 -1` returns every line logged
+// for request req-42.
+//
+// Entries are sent from a background goroutine, so a slow or unreachable Redis
+// never holds up logging. Each exchange times out after a couple of seconds; on
+// a network error the connection is dropped and redialled for the next entry.
+// Failures, and entries dropped because too many are waiting, are reported to the
+// error handler.
+type RedisKVSink struct {
+	addr string
+	ttl  time.Duration
+
+	queue chan redisItem // Entries waiting to be sent
+	done  chan struct{}  // Closed when send has drained the queue
+
+	closeMutex sync.RWMutex // Guards closed and sends to queue
+	closed     bool
+
+	mutex  sync.Mutex // Guards the connection
+	conn   net.Conn   // nil after an error, until the next entry redials
+	reader *bufio.Reader
+}
+
+// redisItem is an entry waiting to be appended to the list at key.
+type redisItem struct {
+	key, value string
+}
+
+// NewRedisKVSink connects to the Redis server at addr (host:port). If ttl is
+// positive each list expires ttl after its latest entry, so old requests clean
+// themselves up. Close the sink to send the entries still waiting.
+func NewRedisKVSink(addr string, ttl time.Duration) (*RedisKVSink, error) {
+	s := &RedisKVSink{
+		addr:  addr,
+		ttl:   ttl,
+		queue: make(chan redisItem, redisQueueSize),
+		done:  make(chan struct{}),
+	}
+	if err := s.dial(); err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	go s.send()
+	return s, nil
+}
+
+// Store queues entry to be appended to the list at key, refreshing the list's TTL.
+// It fails if too many entries are already waiting, or if the sink is closed.
+func (s *RedisKVSink) Store(key string, entry Entry) error {
+	s.closeMutex.RLock()
+	defer s.closeMutex.RUnlock()
+	if s.closed {
+		return fmt.Errorf("redis: the sink is closed, dropped the entry for %s", key)
+	}
+	select {
+	case s.queue <- redisItem{key: key, value: formatJSON(entry)}:
+		return nil
+	default:
+		return fmt.Errorf("redis: %d entries already waiting, dropped the entry for %s", redisQueueSize, key)
+	}
+}
+
+// Close sends the entries still waiting, then closes the connection to Redis.
+// Entries stored afterwards are dropped with an error.
+func (s *RedisKVSink) Close() error {
+	s.closeMutex.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.closeMutex.Unlock()
+	<-s.done
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// send appends the queued entries until the queue is closed.
+func (s *RedisKVSink) send() {
+	defer close(s.done)
+	for item := range s.queue {
+		if err := s.push(item); err != nil {
+			reportError(err)
+		}
+	}
+}
+
+// push appends item to its list and refreshes the TTL, redialling first if the
+// previous exchange failed.
+func (s *RedisKVSink) push(item redisItem) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.conn == nil {
+		if err := s.dial(); err != nil {
+			return fmt.Errorf("redis: dropped the entry for %s: %w", item.key, err)
+		}
+	}
+
+	commands := [][]string{{"RPUSH", item.key, item.value}}
+	if s.ttl > 0 {
+		commands = append(commands, []string{"PEXPIRE", item.key, strconv.FormatInt(int64(s.ttl/time.Millisecond), 10)})
+	}
+	s.conn.SetDeadline(time.Now().Add(redisTimeout))
+	for _, command := range commands { // Pipelined: send everything, then read the replies
+		if _, err := s.conn.Write(respCommand(command)); err != nil {
+			return s.dropConnection(item, err)
+		}
+	}
+	var replyErr error
+	for range commands { // Read every reply, even after an error one, to stay in step
+		err := readRESPReply(s.reader)
+		var redisErr redisError
+		switch {
+		case errors.As(err, &redisErr):
+			if replyErr == nil {
+				replyErr = err
+			}
+		case err != nil:
+			return s.dropConnection(item, err)
+		}
+	}
+	if replyErr != nil {
+		return fmt.Errorf("redis: dropped the entry for %s: %w", item.key, replyErr)
+	}
+	return nil
+}
+
+// dial connects to the server. The caller must hold s.mutex, or be the constructor.
+func (s *RedisKVSink) dial() error {
+	conn, err := net.DialTimeout("tcp", s.addr, redisTimeout)
+	if err != nil {
+		return err
+	}
+	s.conn, s.reader = conn, bufio.NewReader(conn)
+	return nil
+}
+
+// dropConnection closes a connection left in an unknown state by err, so the next
+// entry redials, and returns the error for item. The caller must hold s.mutex.
+func (s *RedisKVSink) dropConnection(item redisItem, err error) error {
+	s.conn.Close()
+	s.conn, s.reader = nil, nil
+	return fmt.Errorf("redis: dropped the entry for %s: %w", item.key, err)
+}
+
+// respCommand encodes a command as a RESP array of bulk strings.
+func respCommand(args []string) []byte {
+	command := []byte(fmt.Sprintf("*%d\r\n", len(args)))
+	for _, arg := range args {
+		command = append(command, fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg)...)
+	}
+	return command
+}
+
+// redisError is an error reply from Redis, which leaves the connection usable.
+type redisError string
+
+// Error implements the error interface.
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// readRESPReply reads a simple reply (status, error or integer), as returned by
+// RPUSH and PEXPIRE, and returns the error it carries (a redisError), if any.
+func readRESPReply(r *bufio.Reader) error {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	switch {
+	case strings.HasPrefix(line, "+"), strings.HasPrefix(line, ":"):
+		return nil
+	case strings.HasPrefix(line, "-"):
+		return redisError(line[1:])
+	default:
+		return fmt.Errorf("unexpected reply %q", line)
+	}
+}
@@ -0,0 +1,282 @@
+package slog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeKVSink records the entries stored under each key.
+type fakeKVSink struct {
+	mutex   sync.Mutex
+	entries map[string][]Entry
+}
+
+func (s *fakeKVSink) Store(key string, entry Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.entries == nil {
+		s.entries = make(map[string][]Entry)
+	}
+	s.entries[key] = append(s.entries[key], entry)
+	return nil
+}
+
+// TestLoggerKVSink ensures entries are stored under their request ID, and that
+// entries without one are only written to the normal output.
+func TestLoggerKVSink(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	logger, sink := newCaptureLogger("API")
+	kv := &fakeKVSink{}
+	logger.SetKVSink(kv)
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"req-1", "req-2"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			SetRequestID(id)
+			defer ClearRequestID()
+			logger.Info("Started %s", id)
+			logger.Info("Finished %s", id)
+		}(id)
+	}
+	wg.Wait()
+	logger.Info("Background job")
+	logger.WithFields(Field{Key: "request_id", Value: 3}).Info("Explicit field")
+
+	if n := len(sink.Entries()); n != 6 {
+		t.Errorf("Expected every entry in the normal output, got %d", n)
+	}
+	if len(kv.entries) != 3 {
+		t.Fatalf("Expected 3 keys, got %v", kv.entries)
+	}
+	for _, id := range []string{"req-1", "req-2"} {
+		entries := kv.entries[id]
+		if len(entries) != 2 || entries[0].Message != "Started "+id || entries[1].Message != "Finished "+id {
+			t.Errorf("Expected the start and finish of %s under its key, got %v", id, entries)
+		}
+	}
+	if entries := kv.entries["3"]; len(entries) != 1 || entries[0].Message != "Explicit field" {
+		t.Errorf("Expected the entry with an explicit request_id under its key, got %v", entries)
+	}
+}
+
+// fakeRedis accepts connections and replies to each RESP command with :1,
+// recording the commands it received. The first hangUps connections are closed
+// after their first command instead, and if stall is set it never replies.
+type fakeRedis struct {
+	listener net.Listener
+	commands chan []string
+	hangUps  int
+	stall    bool
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	return &fakeRedis{listener: listener, commands: make(chan []string, 10)}
+}
+
+// start serves connections until the listener is closed. It must be called after
+// the fields are set.
+func (s *fakeRedis) start() *fakeRedis {
+	go func() {
+		for i := 0; ; i++ {
+			conn, err := s.listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn, i < s.hangUps)
+		}
+	}()
+	return s
+}
+
+func (s *fakeRedis) serve(conn net.Conn, hangUp bool) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		var n int
+		if _, err := fmt.Fscanf(reader, "*%d\r\n", &n); err != nil {
+			return
+		}
+		command := make([]string, n)
+		for i := range command {
+			var size int
+			if _, err := fmt.Fscanf(reader, "$%d\r\n", &size); err != nil {
+				return
+			}
+			arg := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, arg); err != nil {
+				return
+			}
+			command[i] = string(arg[:size])
+		}
+		s.commands <- command
+		switch {
+		case hangUp:
+			return
+		case !s.stall:
+			conn.Write([]byte(":1\r\n"))
+		}
+	}
+}
+
+// TestRedisKVSink ensures entries are pushed as JSON onto the request's list and
+// that the list's TTL is refreshed.
+func TestRedisKVSink(t *testing.T) {
+	server := newFakeRedis(t).start()
+	sink, err := NewRedisKVSink(server.listener.Addr().String(), 90*time.Second)
+	if err != nil {
+		t.Fatalf("Expected NewRedisKVSink to succeed, got %v", err)
+	}
+	defer sink.Close()
+
+	entry := Entry{
+		Time:    time.Date(2022, time.July, 4, 10, 20, 30, 0, time.UTC),
+		Level:   WARN,
+		Message: "Slow query\r\nwith a newline",
+		Fields:  []Field{{Key: "request_id", Value: "req-7"}},
+	}
+	if err := sink.Store("req-7", entry); err != nil {
+		t.Fatalf("Expected Store to succeed, got %v", err)
+	}
+
+	push := <-server.commands
+	if len(push) != 3 || push[0] != "RPUSH" || push[1] != "req-7" {
+		t.Fatalf("Expected RPUSH req-7 <entry>, got %q", push)
+	}
+	var stored map[string]interface{}
+	if err := json.Unmarshal([]byte(push[2]), &stored); err != nil {
+		t.Fatalf("Expected the entry as JSON, got %q", push[2])
+	}
+	if stored["level"] != "WARN" || stored["message"] != entry.Message || stored["time"] != "2022-07-04T10:20:30Z" {
+		t.Errorf("Unexpected stored entry %v", stored)
+	}
+
+	expire := <-server.commands
+	if strings.Join(expire, " ") != "PEXPIRE req-7 "+strconv.Itoa(90000) {
+		t.Errorf("Expected PEXPIRE req-7 90000, got %q", expire)
+	}
+}
+
+// TestRedisKVSinkReconnect ensures a connection broken mid-exchange is reported,
+// dropped and redialled for the next entry.
+func TestRedisKVSinkReconnect(t *testing.T) {
+	recorder := recordErrors(t)
+	server := newFakeRedis(t)
+	server.hangUps = 1
+	server.start()
+	sink, err := NewRedisKVSink(server.listener.Addr().String(), 0)
+	if err != nil {
+		t.Fatalf("Expected NewRedisKVSink to succeed, got %v", err)
+	}
+
+	sink.Store("req-1", Entry{Message: "Lost"})
+	sink.Store("req-2", Entry{Message: "Delivered"})
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Expected Close to succeed, got %v", err)
+	}
+
+	for _, key := range []string{"req-1", "req-2"} {
+		if push := <-server.commands; push[0] != "RPUSH" || push[1] != key {
+			t.Errorf("Expected RPUSH %s, got %q", key, push)
+		}
+	}
+	errs := recorder.Errors()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "req-1") {
+		t.Errorf("Expected only the entry for req-1 to be reported lost, got %v", errs)
+	}
+}
+
+// TestRedisKVSinkStalled ensures a Redis that stops answering doesn't hold up
+// Store, and that the exchange times out and is reported.
+func TestRedisKVSinkStalled(t *testing.T) {
+	originalTimeout := redisTimeout
+	t.Cleanup(func() { redisTimeout = originalTimeout })
+	redisTimeout = 50 * time.Millisecond
+	recorder := recordErrors(t)
+
+	server := newFakeRedis(t)
+	server.stall = true
+	server.start()
+	sink, err := NewRedisKVSink(server.listener.Addr().String(), 0)
+	if err != nil {
+		t.Fatalf("Expected NewRedisKVSink to succeed, got %v", err)
+	}
+
+	begin := time.Now()
+	if err := sink.Store("req-1", Entry{Message: "Waiting"}); err != nil {
+		t.Errorf("Expected Store to queue the entry, got %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 40*time.Millisecond {
+		t.Errorf("Expected Store not to wait for Redis, took %s", elapsed)
+	}
+	sink.Close()
+
+	errs := recorder.Errors()
+	var netErr net.Error
+	if len(errs) != 1 || !errors.As(errs[0], &netErr) || !netErr.Timeout() {
+		t.Errorf("Expected a timeout to be reported, got %v", errs)
+	}
+}
+
+// TestRedisKVSinkStoreAfterClose ensures entries stored after Close, e.g. by a
+// Logger still wired to the sink, are reported as dropped rather than panicking.
+func TestRedisKVSinkStoreAfterClose(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() { SetGlobalMinLevel(originalLevel) })
+	SetGlobalMinLevel(INFO)
+	recorder := recordErrors(t)
+
+	server := newFakeRedis(t).start()
+	sink, err := NewRedisKVSink(server.listener.Addr().String(), 0)
+	if err != nil {
+		t.Fatalf("Expected NewRedisKVSink to succeed, got %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Expected Close to succeed, got %v", err)
+	}
+	if err := sink.Store("req-1", Entry{Message: "Late"}); err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Errorf("Expected Store to fail once closed, got %v", err)
+	}
+
+	logger, _ := newCaptureLogger("API")
+	logger.SetKVSink(sink)
+	SetRequestID("req-2")
+	defer ClearRequestID()
+	logger.Info("Finished")
+	if errs := recorder.Errors(); len(errs) != 1 || !strings.Contains(errs[0].Error(), "req-2") {
+		t.Errorf("Expected the dropped entry to be reported, got %v", errs)
+	}
+}
+
+// TestReadRESPReply ensures Redis error replies are returned as errors.
+func TestReadRESPReply(t *testing.T) {
+	reader := bufio.NewReader(strings.NewReader("+OK\r\n:5\r\n-WRONGTYPE not a list\r\n"))
+	for i := 0; i < 2; i++ {
+		if err := readRESPReply(reader); err != nil {
+			t.Errorf("Expected reply %d to succeed, got %v", i, err)
+		}
+	}
+	if err := readRESPReply(reader); err == nil || err.Error() != "WRONGTYPE not a list" {
+		t.Errorf("Expected the WRONGTYPE error, got %v", err)
+	}
+}
@@ -1,6 +1,9 @@
 package slog
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+)
 
 // LogLevel represents the severity of a log message.
 type LogLevel int
@@ -28,6 +31,25 @@ func (l LogLevel) String() string {
 	case FINE:
 		return "FINE"
 	default:
+		unknownLevelLabelMutex.RLock()
+		defer unknownLevelLabelMutex.RUnlock()
+		if unknownLevelLabel != "" {
+			return unknownLevelLabel
+		}
 		return fmt.Sprintf("UNKNOWN_LOG_LEVEL(%d)", l)
 	}
-}
\ No newline at end of file
+}
+
+// This mutex ensures thread-safe access to unknownLevelLabel
+var unknownLevelLabelMutex sync.RWMutex
+var unknownLevelLabel string // Empty means the default UNKNOWN_LOG_LEVEL(n) format
+
+// SetUnknownLevelLabel sets the label String() returns for a value that isn't one of
+// the defined levels, e.g. "UNKNOWN" or "???", so a bad level doesn't leak its
+// numeric value into output. An empty label restores the default UNKNOWN_LOG_LEVEL(n).
+// It's thread-safe.
+func SetUnknownLevelLabel(label string) {
+	unknownLevelLabelMutex.Lock()
+	defer unknownLevelLabelMutex.Unlock()
+	unknownLevelLabel = label
+}
@@ -1,10 +1,16 @@
 package slog
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 )
 
 // --- Global Log Level Configuration ---
@@ -12,14 +18,46 @@ import (
 // This mutex ensures thread-safe access to the global LOG_LEVEL
 var globalLogLevelMutex sync.RWMutex
 var globalLogLevel LogLevel = INFO // Default to INFO, can be changed via Logger methods
+var globalLogLevelFrozen bool      // When true, SetGlobalMinLevel is rejected
 
 // SetGlobalMinLevel sets the minimum log level for ALL Logger instances.
 // This is useful if you want a single, application-wide log verbosity setting.
+// While the level is frozen (see FreezeGlobalLevel) the call has no effect and is
+// reported to the error handler instead.
 // It's thread-safe.
 func SetGlobalMinLevel(level LogLevel) {
+	globalLogLevelMutex.Lock()
+	frozen := globalLogLevelFrozen
+	if !frozen {
+		globalLogLevel = level
+	}
+	current := globalLogLevel
+	globalLogLevelMutex.Unlock()
+
+	if frozen {
+		reportError(fmt.Errorf("%w: ignored change from %s to %s", ErrGlobalLevelFrozen, current, level))
+	}
+}
+
+// ErrGlobalLevelFrozen is reported when SetGlobalMinLevel is called while the level is frozen.
+var ErrGlobalLevelFrozen = errors.New("global level is frozen")
+
+// FreezeGlobalLevel pins the global minimum level at its current value until
+// UnfreezeGlobalLevel is called, so config reloads or debug endpoints can't change
+// it during a critical section or an experiment.
+// It's thread-safe.
+func FreezeGlobalLevel() {
+	globalLogLevelMutex.Lock()
+	defer globalLogLevelMutex.Unlock()
+	globalLogLevelFrozen = true
+}
+
+// UnfreezeGlobalLevel lets SetGlobalMinLevel change the global level again.
+// It's thread-safe.
+func UnfreezeGlobalLevel() {
 	globalLogLevelMutex.Lock()
 	defer globalLogLevelMutex.Unlock()
-	globalLogLevel = level
+	globalLogLevelFrozen = false
 }
 
 // GetGlobalMinLevel returns the current global minimum log level.
@@ -32,8 +70,78 @@ func GetGlobalMinLevel() LogLevel {
 
 // Logger provides a structured logging utility with configurable levels.
 type Logger struct {
+	// Per-level counters, kept first so they are 64-bit aligned for atomic access on 32-bit platforms.
+	filtered [FINE + 1]uint64 // Lines dropped by level filtering
+	sampled  [FINE + 1]uint64 // Lines seen by the sampler (see SetLevelSampling)
+	written  [FINE + 1]uint64 // Lines written to the output
+	tree     [FINE + 1]uint64 // Lines written by this Logger and those derived from it; only counted on the owner
+
 	internalLogger *log.Logger
-	component      string // New field to store the explicit component/struct name
+	levelLoggers   map[LogLevel]*log.Logger // Per-level outputs that replace internalLogger (see NewSplitFileLogger)
+	jsonLogger     *log.Logger              // When set, also receives each entry as a JSON line (see NewNDJSONLogger)
+	component      string                   // New field to store the explicit component/struct name
+
+	sink       entrySink // When set, entries go to the sink instead of internalLogger
+	closer     io.Closer // Output owned by the logger (e.g. a sink), released by Close
+	closed     uint32    // Set to 1 by Close; accessed atomically
+	root       *Logger   // The Logger this one was derived from, which owns the output; nil if not derived
+	timestamps bool      // When true, each line starts with its timestamp (NewLogger's default)
+
+	fields      []conditionalField // Fixed when the logger is created (see WithFieldIf), so no lock is needed
+	attachments []*attachment      // Fixed when the logger is created (see WithAttachment)
+	meta        []Field            // Fixed when the logger is created (see WithMeta)
+
+	// This mutex ensures thread-safe access to the per-logger settings below
+	settingsMutex  sync.RWMutex
+	reportEventID  bool             // When true, each line gets a unique event_id field
+	reportFunction bool             // When true, each line gets a func field naming the calling function
+	maxRecordBytes int              // Records longer than this are truncated; 0 means unlimited
+	maxFieldValue  int              // Field values rendering longer than this are truncated; 0 means unlimited
+	maxFields      int              // Lines keep at most this many fields; 0 means unlimited
+	maxAttachment  int              // Attachments larger than this are truncated; 0 means the default
+	attachmentDir  string           // Where text output writes attachment sidecar files; "" means os.TempDir()
+	sampling       map[LogLevel]int // Keep 1 in N lines per level; missing levels keep everything
+	timeResolution TimeResolution   // Fractional second digits in timestamps
+	closedBehavior ClosedBehavior   // What happens to lines logged after Close
+	kvSink         KVSink           // Also receives entries with a request ID (see SetKVSink)
+	logUnchanged   bool             // When true, LogChange logs equal old and new values too
+	exitLevel      LogLevel         // Writing a line at this level or more severe exits the process...
+	exitCode       int              // ...with this code; 0 disables it (see SetExitOnLevel)
+	summaryLevel   *LogLevel        // The level Summary logs at; nil means INFO
+	sensitiveKeys  []string         // Lowercased key patterns whose field values are redacted
+	stackWindow    time.Duration    // ERROR lines get a stack on the first occurrence of a message per window
+	linePrefix     string           // Written at the start of each text line
+	ghAnnotations  bool             // When true, ERROR and WARN text lines get GitHub Actions annotation prefixes
+	webhook        *webhookNotifier // Also POSTs severe lines to a webhook; only set on the owner (see NotifyWebhook)
+
+	skipEmptyMessages           bool // When true, lines with an empty message and no fields are dropped
+	skipEmptyMessagesWithFields bool // When true, lines with an empty message but some fields are dropped too
+
+	// This mutex ensures thread-safe access to fieldStack
+	fieldStackMutex sync.RWMutex
+	fieldStack      []Field // Fields pushed with PushField, innermost last
+
+	// This mutex ensures thread-safe access to suppressions
+	suppressionsMutex sync.RWMutex
+	suppressions      []*Suppression // Patterns whose matching messages are dropped
+
+	// This mutex ensures thread-safe access to rememberLast and lastLine
+	lastLineMutex sync.RWMutex
+	rememberLast  bool   // When true, the last formatted line is kept for Last()
+	lastLine      string // The most recently written line (without the timestamp)
+
+	// This mutex ensures thread-safe access to stackSeen
+	stackSeenMutex sync.Mutex
+	stackSeen      map[string]*stackOccurrence // ERROR messages seen within the stack window (see SetFirstErrorStacks)
+
+	// This mutex ensures thread-safe access to histograms and observeStop
+	observationsMutex sync.Mutex
+	histograms        map[string]*histogram // Values recorded by Observe since the last flush
+	observeStop       chan struct{}         // Closed to stop the periodic flush (see SetObservationInterval)
+
+	// This mutex ensures thread-safe access to observers
+	observersMutex sync.Mutex
+	observers      map[*Logger]struct{} // Derived Loggers with a periodic flush; only set on the owner
 }
 
 // NewLogger creates and returns a new Logger instance.
@@ -46,29 +154,468 @@ func NewLogger(component string, output *os.File) *Logger {
 		output = os.Stdout
 	}
 	return &Logger{
-		internalLogger: log.New(output, "", log.LstdFlags),
+		internalLogger: log.New(output, "", 0), // Timestamps are added by write
 		component:      component,
+		timestamps:     true,
 	}
 }
 
-// logf is the internal function that handles the actual logging logic.
-// It checks against the global minimum log level and includes the component name.
+// Close flushes and releases any output owned by the logger, such as a sink's
+// pending batch, after logging any pending observations (see Observe). Loggers
+// created by NewLogger don't own their output file, so Close leaves it open.
+// Lines logged afterwards, through this Logger or any derived from it, are
+// dropped (see SetClosedBehavior). Closing a derived Logger only affects that one;
+// closing the Logger it was derived from also stops the webhook (see NotifyWebhook)
+// and the periodic observation flush of every derived Logger.
+func (l *Logger) Close() error {
+	l.SetObservationInterval(0)
+	l.FlushObservations()
+	if l.root == nil {
+		l.stopDerivedObservers()
+		l.NotifyWebhook("", 0)
+	}
+	atomic.StoreUint32(&l.closed, 1)
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+// timestampLayout matches the date and time written by log.LstdFlags.
+const timestampLayout = "2006/01/02 15:04:05"
+
+// logf logs a message stamped with the current time.
 func (l *Logger) logf(level LogLevel, msg string, params ...interface{}) {
-	// Check if the message's level is higher than the currently configured global minimum level.
-	if level > GetGlobalMinLevel() {
-		msg = ""
-		params = nil
-		return // Do not log if the level is too low
+	l.logAtf(time.Time{}, level, msg, params...)
+}
+
+// logAtf is the internal function that handles the actual logging logic.
+// It checks against the global minimum log level and includes the component name.
+// The line is stamped with t, or with the current time if t is zero.
+func (l *Logger) logAtf(t time.Time, level LogLevel, msg string, params ...interface{}) {
+	if !l.admits(level) {
+		return
+	}
+
+	// Most calls are a plain message; skip fmt when Sprintf would return it unchanged.
+	message := msg
+	if len(params) > 0 || strings.IndexByte(msg, '%') >= 0 {
+		message = sprintfPrintable(msg, params...)
+	}
+	entry, ok := l.newEntry(t, level, message)
+	if !ok {
+		return
+	}
+	l.write(entry)
+}
+
+// logFields logs message, which isn't a format string, with extra fields after
+// the logger's own. It's the entry point for helpers like LogChange.
+func (l *Logger) logFields(level LogLevel, message string, extra ...Field) {
+	if !l.admits(level) {
+		return
+	}
+	if entry, ok := l.newEntry(time.Time{}, level, message, extra...); ok {
+		l.write(entry)
+	}
+}
+
+// newEntry builds the entry for message with the logger's fields followed by
+// extra, stamped with t or with the current time if t is zero. It reports false
+// if the message is suppressed or is an empty message that should be skipped.
+func (l *Logger) newEntry(t time.Time, level LogLevel, message string, extra ...Field) (Entry, bool) {
+	if l.isSuppressed(message) {
+		return Entry{}, false
+	}
+	fields := append(l.entryFields(level), extra...)
+
+	l.settingsMutex.RLock()
+	reportEventID := l.reportEventID
+	reportFunction := l.reportFunction
+	skipEmptyMessages := l.skipEmptyMessages
+	skipEmptyMessagesWithFields := l.skipEmptyMessagesWithFields
+	l.settingsMutex.RUnlock()
+
+	// Drop empty log calls if configured, they're usually a bug.
+	if message == "" && ((len(fields) == 0 && skipEmptyMessages) || (len(fields) > 0 && skipEmptyMessagesWithFields)) {
+		return Entry{}, false
+	}
+
+	resolveLazyValues(fields)     // Once, before anything renders them
+	l.redactFields(fields)        // Before truncation, which renders maps as text
+	l.truncateFieldValues(fields) // Attachments have their own cap
+	if reportFunction {
+		fields = append(fields, Field{Key: "func", Value: callerFunction()})
+	}
+	fields = append(fields, l.attachmentFields()...)
+	if reportEventID {
+		fields = append(fields, Field{Key: "event_id", Value: eventIDs.next()})
+	}
+	if t.IsZero() {
+		t = now()
+	}
+	fields = append(fields, l.stackFields(level, message, t)...)
+	fields = l.limitFields(fields)
+	var meta []Field
+	if len(l.meta) > 0 {
+		meta = append(meta, l.meta...)
+	}
+	resolveLazyValues(meta)
+	l.redactFields(meta)
+	l.truncateFieldValues(meta)
+	component := l.component
+	if override, ok := goroutineComponents.get(); ok {
+		component = override
+	}
+	return Entry{
+		Time:      t,
+		Level:     level,
+		Component: component,
+		Message:   message,
+		Fields:    fields,
+		Meta:      meta,
+	}, true
+}
+
+// admits reports whether a line at level gets past the global minimum level, the
+// closed check (see SetClosedBehavior) and sampling, in that order, so lines that
+// would never have been written aren't reported as dropped after Close.
+func (l *Logger) admits(level LogLevel) bool {
+	return !l.isFiltered(level) && !l.isClosed(level) && l.isSampled(level)
+}
+
+// owner returns the Logger that owns l's output: the one l was derived from, or l itself.
+func (l *Logger) owner() *Logger {
+	if l.root != nil {
+		return l.root
+	}
+	return l
+}
+
+// isFiltered reports whether level is below the global minimum level, counting the dropped line if so.
+func (l *Logger) isFiltered(level LogLevel) bool {
+	if level <= GetGlobalMinLevel() {
+		return false
+	}
+	if level >= ERROR && level <= FINE {
+		atomic.AddUint64(&l.filtered[level], 1)
+	}
+	return true
+}
+
+// write hands entry to the logger's sink if it has one, or prints it as a text line otherwise.
+func (l *Logger) write(entry Entry) {
+	l.writeBlock([]Entry{entry})
+}
+
+// writeBlock writes entries, which must share a level, as one block: text lines
+// are printed with a single write so lines from other goroutines can't land
+// between them. Sinks receive the entries one after the other.
+func (l *Logger) writeBlock(entries []Entry) {
+	if len(entries) == 0 {
+		return
+	}
+	defer l.exitIfSevere(entries)
+	for _, entry := range entries {
+		if entry.Level >= ERROR && entry.Level <= FINE {
+			atomic.AddUint64(&l.written[entry.Level], 1)
+			atomic.AddUint64(&l.owner().tree[entry.Level], 1)
+		}
+	}
+	// Text lines are only built if the text output or Last needs them.
+	l.lastLineMutex.RLock()
+	needText := l.rememberLast || (l.sink == nil && l.internalLogger != nil)
+	l.lastLineMutex.RUnlock()
+	var lines []string
+	if needText {
+		lines = make([]string, len(entries))
+		for i, entry := range entries {
+			lines[i] = l.formatText(entry)
+		}
+		l.rememberLine(lines[len(lines)-1])
+	}
+	l.storeEntries(entries)
+	l.notifyWebhook(entries, lines)
+
+	if l.sink != nil {
+		for _, entry := range entries {
+			if err := l.sink.writeEntry(entry); err != nil {
+				reportError(err)
+			}
+		}
+		return
 	}
 
-	// Build the prefix: [LEVEL][COMPONENT]
-	prefix := fmt.Sprintf("[%s]", level.String())
-	if l.component != "" {
-		prefix = fmt.Sprintf("%s[%s]", prefix, l.component)
+	if l.jsonLogger != nil {
+		jsonLines := make([]string, len(entries))
+		for i, entry := range entries {
+			jsonLines[i] = l.formatJSONRecord(entry)
+		}
+		if err := l.jsonLogger.Output(2, strings.Join(jsonLines, "\n")); err != nil {
+			reportError(err)
+		}
+	}
+	if l.internalLogger == nil {
+		return // JSON only
+	}
+
+	level := entries[0].Level
+	l.settingsMutex.RLock()
+	layout := l.timeResolution.layout()
+	prefix := l.linePrefix
+	if l.ghAnnotations {
+		prefix = githubAnnotations[level] + prefix
+	}
+	l.settingsMutex.RUnlock()
+
+	for i, entry := range entries {
+		if l.timestamps {
+			lines[i] = fmt.Sprintf("%s %s", entry.Time.Local().Format(layout), lines[i])
+		}
+		lines[i] = prefix + lines[i]
 	}
 
 	// Print the final message.
-	l.internalLogger.Printf("%s %s", prefix, fmt.Sprintf(msg, params...))
+	output := l.internalLogger
+	if levelLogger, ok := l.levelLoggers[level]; ok {
+		output = levelLogger
+	}
+	if err := output.Output(2, strings.Join(lines, "\n")); err != nil {
+		reportError(err)
+	}
+}
+
+// --- Filtered Line Counts ---
+
+// FilteredStats returns how many lines this Logger has dropped per level because
+// they were finer than the global minimum level, i.e. how much more output raising
+// the verbosity would produce. Lines at unknown levels aren't counted.
+// It's thread-safe.
+func (l *Logger) FilteredStats() map[LogLevel]uint64 {
+	stats := make(map[LogLevel]uint64, len(l.filtered))
+	for level := range l.filtered {
+		stats[LogLevel(level)] = atomic.LoadUint64(&l.filtered[level])
+	}
+	return stats
+}
+
+// WrittenStats returns how many lines this Logger has written per level.
+// Lines at unknown levels aren't counted.
+// It's thread-safe.
+func (l *Logger) WrittenStats() map[LogLevel]uint64 {
+	stats := make(map[LogLevel]uint64, len(l.written))
+	for level := range l.written {
+		stats[LogLevel(level)] = atomic.LoadUint64(&l.written[level])
+	}
+	return stats
+}
+
+// --- Event IDs ---
+
+// SetReportEventID enables or disables appending a unique event_id to each line.
+// IDs are UUIDv7s, so lines from the same process sort in the order they were logged.
+// It's thread-safe.
+func (l *Logger) SetReportEventID(report bool) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.reportEventID = report
+}
+
+// --- Timestamp Resolution ---
+
+// TimeResolution is the precision of the timestamp at the start of each line.
+type TimeResolution int
+
+const (
+	Seconds TimeResolution = iota // 2006/01/02 15:04:05, as written by log.LstdFlags
+	Millis                        // 2006/01/02 15:04:05.000
+	Micros                        // 2006/01/02 15:04:05.000000
+	Nanos                         // 2006/01/02 15:04:05.000000000
+)
+
+// layout returns the time layout for the resolution.
+func (r TimeResolution) layout() string {
+	switch r {
+	case Millis:
+		return timestampLayout + ".000"
+	case Micros:
+		return timestampLayout + ".000000"
+	case Nanos:
+		return timestampLayout + ".000000000"
+	default:
+		return timestampLayout
+	}
+}
+
+// SetTimeResolution sets how precise line timestamps are, e.g. Micros to order
+// events within the same second. The default is Seconds.
+// It's thread-safe.
+func (l *Logger) SetTimeResolution(resolution TimeResolution) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.timeResolution = resolution
+}
+
+// --- Line Prefixes ---
+
+// githubAnnotations are the workflow commands that make GitHub Actions surface a line as an annotation.
+var githubAnnotations = map[LogLevel]string{
+	ERROR: "::error::",
+	WARN:  "::warning::",
+}
+
+// SetLinePrefix sets a string written at the start of each text line, before the
+// timestamp, e.g. ">>> " to make lines stand out in development. The default is
+// no prefix. Sinks don't use it.
+// It's thread-safe.
+func (l *Logger) SetLinePrefix(prefix string) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.linePrefix = prefix
+}
+
+// SetGitHubActionsAnnotations controls whether ERROR and WARN text lines start with
+// "::error::" and "::warning::", so GitHub Actions shows them as annotations in
+// the CI UI. The annotation comes before any SetLinePrefix prefix. The default is false.
+// It's thread-safe.
+func (l *Logger) SetGitHubActionsAnnotations(enabled bool) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.ghAnnotations = enabled
+}
+
+// --- Empty Messages ---
+
+// SetSkipEmptyMessages controls whether a call like logger.Info("") is dropped
+// instead of writing a line with just the prefix. It only applies to lines that
+// carry no fields; see SetSkipEmptyMessagesWithFields for those.
+// It's thread-safe.
+func (l *Logger) SetSkipEmptyMessages(skip bool) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.skipEmptyMessages = skip
+}
+
+// SetSkipEmptyMessagesWithFields controls whether a line with an empty message is
+// dropped even though it carries fields. By default such lines are written, as the
+// fields may be the whole point of the call.
+// It's thread-safe.
+func (l *Logger) SetSkipEmptyMessagesWithFields(skip bool) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.skipEmptyMessagesWithFields = skip
+}
+
+// --- Record Size ---
+
+// SetMaxRecordBytes caps the size of each record, not counting its timestamp.
+// A longer record is cut to n bytes and followed by a diagnostic noting its original size,
+// which guards against a pathological value (e.g. a huge struct via %+v) flooding the output.
+// JSON records (see NewNDJSONLogger) are cut so they stay valid JSON instead.
+// Zero or a negative n means unlimited, which is the default.
+// It's thread-safe.
+func (l *Logger) SetMaxRecordBytes(n int) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.maxRecordBytes = n
+}
+
+// SetMaxFieldValueLength caps the rendered length of each field value. A longer
+// value, such as a huge SQL query or JSON blob, is cut to n bytes and followed by
+// "…(+N bytes)" with the number of bytes cut, in every output, while the rest of
+// the line is kept. Truncated values are rendered as text first, so a field holding
+// a Lazy value is computed when the line is built. Zero or a negative n means
+// unlimited, which is the default.
+// It's thread-safe.
+func (l *Logger) SetMaxFieldValueLength(n int) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.maxFieldValue = n
+}
+
+// SetMaxFields caps the number of fields on a line at n. Fields beyond the first n,
+// counting every source (WithFields, pushed fields, request_id, event_id and the
+// like, in the order they appear on the line), are dropped and replaced by a
+// single fields_truncated=N field with the number dropped. Zero or a negative n
+// means unlimited, which is the default.
+// It's thread-safe.
+func (l *Logger) SetMaxFields(n int) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.maxFields = n
+}
+
+// limitFields applies the logger's SetMaxFields cap to fields.
+func (l *Logger) limitFields(fields []Field) []Field {
+	l.settingsMutex.RLock()
+	maxFields := l.maxFields
+	l.settingsMutex.RUnlock()
+	if maxFields <= 0 || len(fields) <= maxFields {
+		return fields
+	}
+	return append(fields[:maxFields:maxFields], Field{Key: "fields_truncated", Value: len(fields) - maxFields})
+}
+
+// truncateFieldValues replaces values rendering longer than the logger's
+// SetMaxFieldValueLength cap with their truncated text, in place.
+func (l *Logger) truncateFieldValues(fields []Field) {
+	l.settingsMutex.RLock()
+	maxFieldValue := l.maxFieldValue
+	l.settingsMutex.RUnlock()
+	if maxFieldValue <= 0 {
+		return
+	}
+	for i := range fields {
+		text := formatFieldValue(fields[i].Value)
+		if len(text) > maxFieldValue {
+			truncated := truncateUTF8(text, maxFieldValue)
+			fields[i].Value = fmt.Sprintf("%s…(+%d bytes)", truncated, len(text)-len(truncated))
+		}
+	}
+}
+
+// truncateUTF8 returns the longest prefix of s that is at most n bytes
+// and doesn't split a multi-byte character.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// --- Last Line Inspection ---
+
+// SetRememberLast enables or disables keeping a copy of the last formatted line.
+// Disabling it also discards any line remembered so far.
+// It's thread-safe.
+func (l *Logger) SetRememberLast(remember bool) {
+	l.lastLineMutex.Lock()
+	defer l.lastLineMutex.Unlock()
+	l.rememberLast = remember
+	if !remember {
+		l.lastLine = ""
+	}
+}
+
+// Last returns the most recently written line, without its timestamp. It returns an empty string if SetRememberLast(true) has
+// not been called or nothing has been logged since.
+// It's thread-safe.
+func (l *Logger) Last() string {
+	l.lastLineMutex.RLock()
+	defer l.lastLineMutex.RUnlock()
+	return l.lastLine
+}
+
+// rememberLine stores line for Last() if remembering is enabled.
+func (l *Logger) rememberLine(line string) {
+	l.lastLineMutex.Lock()
+	defer l.lastLineMutex.Unlock()
+	if l.rememberLast {
+		l.lastLine = line
+	}
 }
 
 //LOG LEVEL METHODS.
@@ -97,3 +644,32 @@ func (l *Logger) Debug(msg string, params ...interface{}) {
 func (l *Logger) Fine(msg string, params ...interface{}) {
 	l.logf(FINE, msg, params...)
 }
+
+//EXPLICIT TIMESTAMP METHODS.
+//These stamp the line with the supplied event time instead of the current time,
+//e.g. when backfilling logs from archived events.
+
+// ErrorAt logs an error message that happened at t.
+func (l *Logger) ErrorAt(t time.Time, msg string, params ...interface{}) {
+	l.logAtf(t, ERROR, msg, params...)
+}
+
+// WarnAt logs a warning message that happened at t.
+func (l *Logger) WarnAt(t time.Time, msg string, params ...interface{}) {
+	l.logAtf(t, WARN, msg, params...)
+}
+
+// InfoAt logs an informational message that happened at t.
+func (l *Logger) InfoAt(t time.Time, msg string, params ...interface{}) {
+	l.logAtf(t, INFO, msg, params...)
+}
+
+// DebugAt logs a debug message that happened at t.
+func (l *Logger) DebugAt(t time.Time, msg string, params ...interface{}) {
+	l.logAtf(t, DEBUG, msg, params...)
+}
+
+// FineAt logs a fine-grained debug message that happened at t.
+func (l *Logger) FineAt(t time.Time, msg string, params ...interface{}) {
+	l.logAtf(t, FINE, msg, params...)
+}
@@ -2,25 +2,55 @@ package slog
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 // Helper function to create a test logger that writes to a bytes.Buffer
-// and doesn't include standard log flags (like date/time) for easier string comparison.
+// without timestamps, for easier string comparison.
 func newTestLogger(output io.Writer, component string) *Logger {
-	// Temporarily create a log.Logger directly for testing purposes.
-	// In production code, NewLogger always uses log.LstdFlags.
+	// NewLogger also creates its log.Logger with 0 flags, but sets timestamps so
+	// write prefixes each line with the time at the configured resolution (see
+	// SetTimeResolution). Leaving timestamps false keeps the lines deterministic.
 	return &Logger{
 		internalLogger: log.New(output, "", 0), // 0 flags for clean output
 		component:      component,
 	}
 }
 
+// captureSink is an entrySink that keeps every entry it receives, for tests of
+// structured output.
+type captureSink struct {
+	mutex   sync.Mutex
+	entries []Entry
+}
+
+func (s *captureSink) writeEntry(entry Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// Entries returns a copy of the entries received so far.
+func (s *captureSink) Entries() []Entry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Entry(nil), s.entries...)
+}
+
+// newCaptureLogger creates a test logger that hands its entries to a captureSink.
+func newCaptureLogger(component string) (*Logger, *captureSink) {
+	sink := &captureSink{}
+	return &Logger{sink: sink, component: component}, sink
+}
+
 // TestSetGlobalMinLevel ensures the global log level can be set correctly.
 func TestSetGlobalMinLevel(t *testing.T) {
 	// Ensure we reset the global log level after the test
@@ -220,6 +250,7 @@ func TestLogLevelStringer(t *testing.T) {
 		{DEBUG, "DEBUG"},
 		{FINE, "FINE"},
 		{LogLevel(99), "UNKNOWN_LOG_LEVEL(99)"}, // Test an unknown level
+		{LogLevel(-1), "UNKNOWN_LOG_LEVEL(-1)"}, // Test a negative level
 	}
 
 	for _, tc := range testCases {
@@ -288,6 +319,520 @@ func TestLoggerThreadSafety(t *testing.T) {
 	// indicating the mutex usage is preventing deadlocks during writes.
 }
 
+// TestLoggerRememberLast ensures Last() returns the most recently logged line
+// only while remembering is enabled.
+func TestLoggerRememberLast(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(FINE)
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Peek")
+
+	logger.Info("Not remembered")
+	if last := logger.Last(); last != "" {
+		t.Errorf("Expected Last() to be empty before SetRememberLast(true), got %q", last)
+	}
+
+	logger.SetRememberLast(true)
+	logger.Info("First message")
+	logger.Warn("Second message %d", 2)
+	logger.Debug("Third message")
+	SetGlobalMinLevel(INFO)
+	logger.Fine("Filtered message") // Filtered out, so it must not replace the last line
+
+	expected := "[DEBUG][Peek] Third message"
+	if last := logger.Last(); last != expected {
+		t.Errorf("Expected Last() to be %q, got %q", expected, last)
+	}
+
+	logger.SetRememberLast(false)
+	if last := logger.Last(); last != "" {
+		t.Errorf("Expected Last() to be empty after SetRememberLast(false), got %q", last)
+	}
+}
+
+// TestLoggerExplicitTimestamps ensures the *At methods stamp lines with the supplied
+// time rather than the clock, even when the times are out of order.
+func TestLoggerExplicitTimestamps(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(FINE)
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Backfill")
+	logger.timestamps = true // As set by NewLogger
+
+	later := time.Date(2021, time.March, 14, 15, 9, 26, 0, time.UTC)
+	earlier := later.Add(-48 * time.Hour)
+
+	logger.InfoAt(later, "Order %d shipped", 7)
+	logger.ErrorAt(earlier, "Order %d failed", 3) // Backfilled out of order
+	logger.FineAt(later, "Done")
+
+	expected := []string{
+		later.Local().Format(timestampLayout) + " [INFO][Backfill] Order 7 shipped",
+		earlier.Local().Format(timestampLayout) + " [ERROR][Backfill] Order 3 failed",
+		later.Local().Format(timestampLayout) + " [FINE][Backfill] Done",
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %d:\n%s", len(expected), len(lines), buf.String())
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Expected line %d to be %q, got %q", i, expected[i], lines[i])
+		}
+	}
+}
+
+// TestLoggerMaxRecordBytes ensures oversized records are capped with a diagnostic
+// noting their original size, without splitting multi-byte characters.
+func TestLoggerMaxRecordBytes(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	type payload struct {
+		Items []string
+	}
+	huge := payload{Items: make([]string, 10000)}
+	for i := range huge.Items {
+		huge.Items[i] = "item"
+	}
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Cap")
+	logger.SetReportEventID(true) // The cap covers the whole record, fields included
+	logger.SetMaxRecordBytes(100)
+
+	logger.Info("Dump: %+v", huge)
+	output := strings.TrimSpace(buf.String())
+	originalSize := len(fmt.Sprintf("[INFO][Cap] Dump: %+v", huge)) + len(" event_id=") + 36
+
+	expectedPrefix := "[INFO][Cap] Dump: {Items:[item item"
+	expectedSuffix := fmt.Sprintf(" …[record truncated, original_size=%d bytes]", originalSize)
+	if !strings.HasPrefix(output, expectedPrefix) || !strings.HasSuffix(output, expectedSuffix) {
+		t.Fatalf("Expected a capped record ending in %q, got %q", expectedSuffix, output)
+	}
+	if kept := len(output) - len(expectedSuffix); kept != 100 {
+		t.Errorf("Expected 100 bytes of the record to be kept, got %d", kept)
+	}
+
+	// A cap falling inside a multi-byte character backs off to the character boundary.
+	buf.Reset()
+	logger.SetReportEventID(false)
+	logger.SetMaxRecordBytes(len("[INFO][Cap] ") + 1)
+	logger.Info("éé")
+	output = strings.TrimSpace(buf.String())
+	if !strings.HasPrefix(output, "[INFO][Cap]  …[record truncated") {
+		t.Errorf("Expected the partial character to be dropped, got %q", output)
+	}
+
+	// Records within the cap, or with the cap disabled, are untouched.
+	buf.Reset()
+	logger.SetMaxRecordBytes(0)
+	logger.Info("Dump: %+v", huge)
+	if strings.Contains(buf.String(), "record truncated") {
+		t.Errorf("Expected no truncation with the cap disabled")
+	}
+}
+
+// TestLoggerSkipEmptyMessages ensures empty messages are dropped only when configured,
+// with lines carrying fields governed by their own setting.
+func TestLoggerSkipEmptyMessages(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	testCases := []struct {
+		name           string
+		skip           bool
+		skipWithFields bool
+		withFields     bool
+		expected       string // Empty when the line should be dropped
+	}{
+		{"DefaultNoFields", false, false, false, "[INFO][Empty] "},
+		{"DefaultWithFields", false, false, true, "[INFO][Empty]  user=42"},
+		{"SkipNoFields", true, false, false, ""},
+		{"SkipKeepsLineWithFields", true, false, true, "[INFO][Empty]  user=42"},
+		{"SkipWithFieldsNoFields", false, true, false, "[INFO][Empty] "},
+		{"SkipBoth", true, true, true, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := newTestLogger(&buf, "Empty")
+			logger.SetSkipEmptyMessages(tc.skip)
+			logger.SetSkipEmptyMessagesWithFields(tc.skipWithFields)
+			if tc.withFields {
+				logger.PushField("user", 42)
+			}
+
+			logger.Info("")
+			logger.Info("%s", "") // Empty once formatted counts too
+
+			output := strings.TrimRight(buf.String(), "\n")
+			expected := ""
+			if tc.expected != "" {
+				expected = tc.expected + "\n" + tc.expected
+			}
+			if output != expected {
+				t.Errorf("Expected output %q, got %q", expected, output)
+			}
+
+			// Non-empty messages are never affected.
+			buf.Reset()
+			logger.Info("Not empty")
+			if !strings.Contains(buf.String(), "Not empty") {
+				t.Errorf("Expected a non-empty message to be logged, got %q", buf.String())
+			}
+		})
+	}
+}
+
+// TestSetUnknownLevelLabel ensures a custom label replaces the default rendering of
+// unknown levels, including negative ones, and that an empty label restores it.
+func TestSetUnknownLevelLabel(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetUnknownLevelLabel("")
+	})
+	SetGlobalMinLevel(INFO)
+
+	SetUnknownLevelLabel("UNKNOWN")
+	testCases := []struct {
+		level    LogLevel
+		expected string
+	}{
+		{LogLevel(99), "UNKNOWN"},
+		{LogLevel(-3), "UNKNOWN"},
+		{WARN, "WARN"}, // Known levels are unaffected
+	}
+	for _, tc := range testCases {
+		if label := tc.level.String(); label != tc.expected {
+			t.Errorf("Expected String() for %d to be %q, got %q", int(tc.level), tc.expected, label)
+		}
+	}
+
+	// The label is what ends up in the output.
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Levels")
+	logger.logf(LogLevel(-3), "Bad level")
+	if output := strings.TrimSpace(buf.String()); output != "[UNKNOWN][Levels] Bad level" {
+		t.Errorf("Expected the custom label in the output, got %q", output)
+	}
+
+	SetUnknownLevelLabel("")
+	if label := LogLevel(-3).String(); label != "UNKNOWN_LOG_LEVEL(-3)" {
+		t.Errorf("Expected the default label after SetUnknownLevelLabel(\"\"), got %q", label)
+	}
+}
+
+// TestLoggerFilteredStats ensures lines dropped by level filtering are counted exactly
+// per level, even when logged concurrently.
+func TestLoggerFilteredStats(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Stats")
+
+	var wg sync.WaitGroup
+	numGoroutines := 20
+	callsPerGoroutine := 500
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < callsPerGoroutine; j++ {
+				logger.Error("e")
+				logger.Info("i")
+				logger.Debug("d")
+				logger.Fine("f")
+				logger.Fine("f")
+			}
+		}()
+	}
+	wg.Wait()
+	logger.logf(LogLevel(99), "Unknown levels aren't counted")
+
+	total := uint64(numGoroutines * callsPerGoroutine)
+	expected := map[LogLevel]uint64{
+		ERROR: 0,
+		WARN:  0,
+		INFO:  0,
+		DEBUG: total,
+		FINE:  2 * total,
+	}
+	stats := logger.FilteredStats()
+	if len(stats) != len(expected) {
+		t.Errorf("Expected stats for %d levels, got %v", len(expected), stats)
+	}
+	for level, count := range expected {
+		if stats[level] != count {
+			t.Errorf("Expected %d filtered %s lines, got %d", count, level, stats[level])
+		}
+	}
+}
+
+// TestLoggerTimeResolution ensures each resolution writes the expected number of
+// fractional second digits for a fixed time.
+func TestLoggerTimeResolution(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	fixed := time.Date(2022, time.July, 4, 10, 20, 30, 123456789, time.Local)
+
+	testCases := []struct {
+		name       string
+		resolution TimeResolution
+		expected   string
+	}{
+		{"Seconds", Seconds, "2022/07/04 10:20:30"},
+		{"Millis", Millis, "2022/07/04 10:20:30.123"},
+		{"Micros", Micros, "2022/07/04 10:20:30.123456"},
+		{"Nanos", Nanos, "2022/07/04 10:20:30.123456789"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := newTestLogger(&buf, "")
+			logger.timestamps = true
+			logger.SetTimeResolution(tc.resolution)
+
+			logger.InfoAt(fixed, "Tick")
+
+			expected := tc.expected + " [INFO] Tick"
+			if output := strings.TrimSpace(buf.String()); output != expected {
+				t.Errorf("Expected %q, got %q", expected, output)
+			}
+		})
+	}
+}
+
+// TestLoggerLinePrefixes ensures the line prefix is written on every line and the
+// GitHub Actions annotations only on ERROR and WARN lines, and only when enabled.
+func TestLoggerLinePrefixes(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(DEBUG)
+
+	testCases := []struct {
+		name        string
+		prefix      string
+		annotations bool
+		expected    []string
+	}{
+		{"Default", "", false, []string{"[ERROR][CI] e", "[WARN][CI] w", "[INFO][CI] i", "[DEBUG][CI] d"}},
+		{"Prefix", ">>> ", false, []string{">>> [ERROR][CI] e", ">>> [WARN][CI] w", ">>> [INFO][CI] i", ">>> [DEBUG][CI] d"}},
+		{"Annotations", "", true, []string{"::error::[ERROR][CI] e", "::warning::[WARN][CI] w", "[INFO][CI] i", "[DEBUG][CI] d"}},
+		{"Both", "> ", true, []string{"::error::> [ERROR][CI] e", "::warning::> [WARN][CI] w", "> [INFO][CI] i", "> [DEBUG][CI] d"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := newTestLogger(&buf, "CI")
+			logger.SetLinePrefix(tc.prefix)
+			logger.SetGitHubActionsAnnotations(tc.annotations)
+
+			logger.Error("e")
+			logger.Warn("w")
+			logger.Info("i")
+			logger.Debug("d")
+
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if strings.Join(lines, "|") != strings.Join(tc.expected, "|") {
+				t.Errorf("Expected lines %q, got %q", tc.expected, lines)
+			}
+		})
+	}
+}
+
+// TestLoggerPlainMessages ensures messages logged without parameters come out
+// exactly as fmt.Sprintf would render them, including stray % verbs.
+func TestLoggerPlainMessages(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	messages := []string{"connection accepted", "100%", "50%% done", "%d items", "%", ""}
+	for _, msg := range messages {
+		var buf bytes.Buffer
+		logger := newTestLogger(&buf, "Net")
+		logger.Info(msg)
+
+		expected := "[INFO][Net] " + fmt.Sprintf(msg) + "\n"
+		if buf.String() != expected {
+			t.Errorf("Info(%q): expected %q, got %q", msg, expected, buf.String())
+		}
+	}
+}
+
+// BenchmarkLoggerInfoPlain measures the common call without parameters, which
+// skips fmt.Sprintf.
+func BenchmarkLoggerInfoPlain(b *testing.B) {
+	originalLevel := GetGlobalMinLevel()
+	defer SetGlobalMinLevel(originalLevel)
+	SetGlobalMinLevel(INFO)
+
+	logger := newTestLogger(io.Discard, "Net")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("connection accepted")
+	}
+}
+
+// BenchmarkLoggerInfoFormatted measures a call with a parameter, for comparison
+// with BenchmarkLoggerInfoPlain.
+func BenchmarkLoggerInfoFormatted(b *testing.B) {
+	originalLevel := GetGlobalMinLevel()
+	defer SetGlobalMinLevel(originalLevel)
+	SetGlobalMinLevel(INFO)
+
+	logger := newTestLogger(io.Discard, "Net")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Info("connection accepted from %s", "10.0.0.1")
+	}
+}
+
+// TestLoggerMaxFieldValueLength ensures long field values are truncated on their
+// own, on a UTF-8 boundary, in both text and JSON output.
+func TestLoggerMaxFieldValueLength(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetClock(nil)
+	})
+	SetGlobalMinLevel(INFO)
+	SetClock(&fakeClock{current: time.Date(2022, time.July, 4, 10, 20, 30, 0, time.UTC)})
+
+	type query struct {
+		Table string
+		Where string
+	}
+	fields := []Field{
+		{Key: "sql", Value: "SELECT * FROM orders WHERE id = 1"},
+		{Key: "q", Value: query{Table: "orders", Where: "id = 1"}},
+		{Key: "name", Value: "Zoë Ñúñez"}, // "Zo" + 2-byte "ë": a cut at 3 bytes must back off
+		{Key: "short", Value: 42},
+	}
+
+	var stdout, stderr bytes.Buffer
+	logger := newNDJSONLogger("DB", &stdout, &stderr, true)
+	logger.timestamps = false
+	logger.SetMaxFieldValueLength(3)
+	logger.WithFields(fields...).Info("Slow query with a long message that isn't truncated")
+
+	expectedText := "[INFO][DB] Slow query with a long message that isn't truncated " +
+		"sql=SEL…(+30 bytes) q={or…(+12 bytes) name=Zo…(+11 bytes) short=42\n"
+	if stderr.String() != expectedText {
+		t.Errorf("Expected text %q, got %q", expectedText, stderr.String())
+	}
+	expectedJSON := `{"time":"2022-07-04T10:20:30Z","level":"INFO","component":"DB","message":"Slow query with a long message that isn't truncated",` +
+		`"fields":{"name":"Zo…(+11 bytes)","q":"{or…(+12 bytes)","short":42,"sql":"SEL…(+30 bytes)"}}` + "\n"
+	if stdout.String() != expectedJSON {
+		t.Errorf("Expected JSON %q, got %q", expectedJSON, stdout.String())
+	}
+
+	// Zero means unlimited.
+	stdout.Reset()
+	stderr.Reset()
+	logger.SetMaxFieldValueLength(0)
+	logger.WithFields(fields[0]).Info("Unlimited")
+	if !strings.Contains(stderr.String(), "sql=SELECT * FROM orders WHERE id = 1") {
+		t.Errorf("Expected the full value without a cap, got %q", stderr.String())
+	}
+}
+
+// TestLoggerSetMaxFields ensures fields beyond the cap, from every source, are
+// replaced by a fields_truncated count.
+func TestLoggerSetMaxFields(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		ClearRequestID()
+	})
+	SetGlobalMinLevel(INFO)
+
+	var buffer bytes.Buffer
+	logger := newTestLogger(&buffer, "Import")
+	logger.SetMaxFields(3)
+	SetRequestID("req-7")
+	logger.PushField("batch", 9)
+	logger.WithFields(Field{Key: "a", Value: 1}, Field{Key: "b", Value: 2}).Info("Row %d", 1)
+
+	expected := "[INFO][Import] Row 1 a=1 b=2 batch=9 fields_truncated=1\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buffer.String())
+	}
+
+	// At the cap nothing is dropped, and zero means unlimited.
+	buffer.Reset()
+	logger.PopField()
+	logger.WithFields(Field{Key: "a", Value: 1}, Field{Key: "b", Value: 2}).Info("Row %d", 2)
+	logger.SetMaxFields(0)
+	logger.WithFields(Field{Key: "a", Value: 1}, Field{Key: "b", Value: 2}, Field{Key: "c", Value: 3}).Info("Row %d", 3)
+	expected = "[INFO][Import] Row 2 a=1 b=2 request_id=req-7\n[INFO][Import] Row 3 a=1 b=2 c=3 request_id=req-7\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buffer.String())
+	}
+}
+
+// TestFreezeGlobalLevel ensures SetGlobalMinLevel has no effect, and is reported,
+// while the level is frozen, and works again once unfrozen.
+func TestFreezeGlobalLevel(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	t.Cleanup(UnfreezeGlobalLevel) // Runs first, so the level can be restored
+	recorder := recordErrors(t)
+
+	SetGlobalMinLevel(WARN)
+	FreezeGlobalLevel()
+	SetGlobalMinLevel(FINE)
+	if level := GetGlobalMinLevel(); level != WARN {
+		t.Errorf("Expected the level to stay WARN while frozen, got %s", level)
+	}
+	errs := recorder.Errors()
+	if len(errs) != 1 || !errors.Is(errs[0], ErrGlobalLevelFrozen) || errs[0].Error() != "global level is frozen: ignored change from WARN to FINE" {
+		t.Errorf("Expected the rejected change to be reported, got %v", errs)
+	}
+
+	UnfreezeGlobalLevel()
+	SetGlobalMinLevel(FINE)
+	if level := GetGlobalMinLevel(); level != FINE {
+		t.Errorf("Expected the level to change after unfreezing, got %s", level)
+	}
+	if n := len(recorder.Errors()); n != 1 {
+		t.Errorf("Expected no more reports after unfreezing, got %d", n-1)
+	}
+}
+
 /**
 Explanation of the Tests:
 newTestLogger Helper:
@@ -0,0 +1,48 @@
+package slog
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestLoggerWithMeta ensures metadata reaches structured outputs, sinks and the
+// JSON output, but not the text output.
+func TestLoggerWithMeta(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetClock(nil)
+	})
+	SetGlobalMinLevel(INFO)
+	SetClock(&fakeClock{current: time.Date(2022, time.July, 4, 10, 20, 30, 0, time.UTC)})
+
+	logger, sink := newCaptureLogger("Billing")
+	logger.WithMeta("account_id", "acct-9").WithFields(Field{Key: "plan", Value: "pro"}).Info("Invoice sent")
+
+	entries := sink.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if expected := []Field{{Key: "account_id", Value: "acct-9"}}; !reflect.DeepEqual(entries[0].Meta, expected) {
+		t.Errorf("Expected meta %v, got %v", expected, entries[0].Meta)
+	}
+	if expected := []Field{{Key: "plan", Value: "pro"}}; !reflect.DeepEqual(entries[0].Fields, expected) {
+		t.Errorf("Expected the metadata to stay out of the fields, got %v", entries[0].Fields)
+	}
+
+	var stdout, stderr bytes.Buffer
+	dual := newNDJSONLogger("Billing", &stdout, &stderr, true)
+	dual.timestamps = false
+	dual.WithMeta("account_id", "acct-9").Info("Invoice sent")
+
+	expectedJSON := `{"time":"2022-07-04T10:20:30Z","level":"INFO","component":"Billing","message":"Invoice sent","meta":{"account_id":"acct-9"}}` + "\n"
+	if stdout.String() != expectedJSON {
+		t.Errorf("Expected %q, got %q", expectedJSON, stdout.String())
+	}
+	if text := stderr.String(); text != "[INFO][Billing] Invoice sent\n" || strings.Contains(text, "acct-9") {
+		t.Errorf("Expected the text output without metadata, got %q", text)
+	}
+}
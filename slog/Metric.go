@@ -0,0 +1,23 @@
+package slog
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Metric logs a metric value as an INFO line, e.g.
+//
+//	logger.Metric("orders_processed", 42, "count")
+//
+// writes "[INFO] orders_processed=42 count metric_name=orders_processed metric_value=42 metric_unit=count".
+// The metric_* fields have fixed names and metric_value is a number in structured
+// outputs, so a log-based metrics extractor (e.g. a CloudWatch metric filter or a
+// Loki recording rule) can pick them up reliably. unit may be empty.
+func (l *Logger) Metric(name string, value float64, unit string) {
+	message := strings.TrimSpace(name + "=" + strconv.FormatFloat(value, 'g', -1, 64) + " " + unit)
+	l.logFields(INFO, message,
+		Field{Key: "metric_name", Value: name},
+		Field{Key: "metric_value", Value: value},
+		Field{Key: "metric_unit", Value: unit},
+	)
+}
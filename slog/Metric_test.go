@@ -0,0 +1,53 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestLoggerMetric ensures the metric fields appear with their types: the value
+// as a number in JSON and text output alike.
+func TestLoggerMetric(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetClock(nil)
+	})
+	SetGlobalMinLevel(INFO)
+	SetClock(&fakeClock{current: time.Date(2022, time.July, 4, 10, 20, 30, 0, time.UTC)})
+
+	var stdout, stderr bytes.Buffer
+	logger := newNDJSONLogger("Jobs", &stdout, &stderr, true)
+	logger.timestamps = false
+	logger.Metric("batch_duration", 1.25, "s")
+
+	if expected := "[INFO][Jobs] batch_duration=1.25 s metric_name=batch_duration metric_value=1.25 metric_unit=s\n"; stderr.String() != expected {
+		t.Errorf("Expected text %q, got %q", expected, stderr.String())
+	}
+
+	var decoded struct {
+		Level  string
+		Fields map[string]interface{}
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &decoded); err != nil {
+		t.Fatalf("Expected a JSON line, got %q", stdout.String())
+	}
+	if decoded.Level != "INFO" {
+		t.Errorf("Expected an INFO line, got %s", decoded.Level)
+	}
+	if value, ok := decoded.Fields["metric_value"].(float64); !ok || value != 1.25 {
+		t.Errorf("Expected metric_value to be the number 1.25, got %#v", decoded.Fields["metric_value"])
+	}
+	if decoded.Fields["metric_name"] != "batch_duration" || decoded.Fields["metric_unit"] != "s" {
+		t.Errorf("Expected metric_name and metric_unit strings, got %v", decoded.Fields)
+	}
+
+	// Without a unit the message has no trailing space.
+	stderr.Reset()
+	logger.Metric("queue_depth", 3, "")
+	if expected := "[INFO][Jobs] queue_depth=3 metric_name=queue_depth metric_value=3 metric_unit=\n"; stderr.String() != expected {
+		t.Errorf("Expected text %q, got %q", expected, stderr.String())
+	}
+}
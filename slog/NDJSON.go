@@ -0,0 +1,43 @@
+package slog
+
+import (
+	"io"
+	"log"
+	"os"
+)
+
+// NewNDJSONLogger creates a Logger for containers: every line is written to
+// os.Stdout as a JSON object per line (NDJSON) for the log pipeline, e.g.
+//
+//	{"time":"2022-07-04T10:20:30Z","level":"INFO","component":"API","message":"Started","fields":{"port":8080}}
+//
+// and, when os.Stderr is a terminal (e.g. `kubectl run -it` or a local `docker
+// run -t`), duplicated to os.Stderr as the usual human-readable text.
+// Text-only settings such as SetLinePrefix only apply to the stderr copy.
+func NewNDJSONLogger(component string) *Logger {
+	return newNDJSONLogger(component, os.Stdout, os.Stderr, isTerminal(os.Stderr))
+}
+
+// newNDJSONLogger creates a Logger writing NDJSON to stdout and, if stderrIsTTY,
+// text to stderr.
+func newNDJSONLogger(component string, stdout, stderr io.Writer, stderrIsTTY bool) *Logger {
+	logger := &Logger{
+		jsonLogger: log.New(stdout, "", 0),
+		component:  component,
+		timestamps: true,
+	}
+	if stderrIsTTY {
+		logger.internalLogger = log.New(stderr, "", 0) // Timestamps are added by write
+	}
+	return logger
+}
+
+// isTerminal reports whether file is a character device, i.e. a terminal rather
+// than a pipe or a regular file.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
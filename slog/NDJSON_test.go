@@ -0,0 +1,100 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNDJSONLogger ensures every line goes to stdout as a JSON object, and to
+// stderr as text only when stderr is a terminal.
+func TestNDJSONLogger(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetClock(nil)
+	})
+	SetGlobalMinLevel(INFO)
+	SetClock(&fakeClock{current: time.Date(2022, time.July, 4, 10, 20, 30, 0, time.UTC)})
+
+	for _, tty := range []bool{true, false} {
+		var stdout, stderr bytes.Buffer
+		logger := newNDJSONLogger("API", &stdout, &stderr, tty)
+		logger.timestamps = false
+		logger.WithFields(Field{Key: "port", Value: 8080}).Info("Started")
+		logger.LogBatch(WARN, "Slow requests", []string{"GET /a"})
+
+		lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+		expected := []string{
+			`{"time":"2022-07-04T10:20:30Z","level":"INFO","component":"API","message":"Started","fields":{"port":8080}}`,
+			`{"time":"2022-07-04T10:20:30Z","level":"WARN","component":"API","message":"Slow requests","fields":{"batch_size":1}}`,
+			`{"time":"2022-07-04T10:20:30Z","level":"WARN","component":"API","message":"GET /a"}`,
+		}
+		if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("tty=%v: expected NDJSON\n%s\ngot\n%s", tty, strings.Join(expected, "\n"), stdout.String())
+		}
+		for _, line := range lines {
+			if !json.Valid([]byte(line)) {
+				t.Errorf("Expected a JSON object per line, got %q", line)
+			}
+		}
+
+		expectedText := ""
+		if tty {
+			expectedText = "[INFO][API] Started port=8080\n[WARN][API] Slow requests batch_size=1\n[WARN][API] GET /a\n"
+		}
+		if stderr.String() != expectedText {
+			t.Errorf("tty=%v: expected stderr %q, got %q", tty, expectedText, stderr.String())
+		}
+	}
+}
+
+// TestNDJSONLoggerRecordCapAndAttachments ensures the record size cap applies to
+// the JSON lines, which stay valid JSON, and that attachments reach them
+// base64-encoded rather than as sidecar paths.
+func TestNDJSONLoggerRecordCapAndAttachments(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetClock(nil)
+	})
+	SetGlobalMinLevel(INFO)
+	SetClock(&fakeClock{current: time.Date(2022, time.July, 4, 10, 20, 30, 0, time.UTC)})
+
+	var stdout, stderr bytes.Buffer
+	logger := newNDJSONLogger("API", &stdout, &stderr, false)
+	logger.SetMaxRecordBytes(150)
+	logger.WithFields(Field{Key: "user", Value: "alice"}).Info("%s", strings.Repeat("\"quoted\" ", 50))
+
+	line := strings.TrimSuffix(stdout.String(), "\n")
+	if len(line) > 150 || !json.Valid([]byte(line)) {
+		t.Errorf("Expected valid JSON within 150 bytes, got %d bytes: %s", len(line), line)
+	}
+	expectedPrefix := `{"time":"2022-07-04T10:20:30Z","level":"INFO","component":"API","message":"\"quoted\" \"quoted\"`
+	if !strings.HasPrefix(line, expectedPrefix) || !strings.HasSuffix(line, `","fields":{"record_truncated_from":653}}`) {
+		t.Errorf("Expected the message cut and the original size noted, got %s", line)
+	}
+
+	stdout.Reset()
+	logger.SetMaxRecordBytes(0)
+	logger.WithAttachment("payload", []byte{0xff, 0x00}).Error("Rejected")
+	expected := `{"time":"2022-07-04T10:20:30Z","level":"ERROR","component":"API","message":"Rejected","fields":{"attachments":{"payload":"/wA="}}}` + "\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, stdout.String())
+	}
+}
+
+// TestIsTerminal ensures regular files aren't mistaken for terminals.
+func TestIsTerminal(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatalf("Failed to create a file: %v", err)
+	}
+	defer file.Close()
+	if isTerminal(file) {
+		t.Errorf("Expected a regular file not to be a terminal")
+	}
+}
@@ -0,0 +1,174 @@
+package slog
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// maxObservationSamples bounds the memory used per name by Observe. Beyond it,
+// percentiles are estimated from a uniform sample of the values.
+const maxObservationSamples = 1024
+
+// histogram accumulates the values observed for one name. count, min and max are
+// exact; samples is a reservoir sample used for the percentiles.
+type histogram struct {
+	count    uint64
+	min, max float64
+	samples  []float64
+}
+
+// add records value, replacing a random sample once the reservoir is full so
+// every value seen so far is equally likely to be kept.
+func (h *histogram) add(value float64) {
+	h.count++
+	if h.count == 1 || value < h.min {
+		h.min = value
+	}
+	if h.count == 1 || value > h.max {
+		h.max = value
+	}
+	if len(h.samples) < maxObservationSamples {
+		h.samples = append(h.samples, value)
+		return
+	}
+	if i := rand.Int63n(int64(h.count)); i < maxObservationSamples {
+		h.samples[i] = value
+	}
+}
+
+// summary returns the count, min, p50, p90, p99 and max fields.
+func (h *histogram) summary() []Field {
+	sort.Float64s(h.samples)
+	return []Field{
+		{Key: "count", Value: h.count},
+		{Key: "min", Value: h.min},
+		{Key: "p50", Value: percentile(h.samples, 50)},
+		{Key: "p90", Value: percentile(h.samples, 90)},
+		{Key: "p99", Value: percentile(h.samples, 99)},
+		{Key: "max", Value: h.max},
+	}
+}
+
+// percentile returns the nearest-rank pth percentile of sorted, which must not be empty.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Observe records value under name, e.g. a request latency, instead of logging it.
+// Values are summarised by FlushObservations, either explicitly or periodically
+// (see SetObservationInterval), as one INFO line per name:
+//
+//	[INFO][API] db_latency_ms count=5000 min=0.4 p50=2.1 p90=7.8 p99=31 max=120.5
+//
+// Memory per name is bounded, so percentiles over many values are estimates.
+// Each Logger, including derived ones, keeps its own observations.
+// It's thread-safe.
+func (l *Logger) Observe(name string, value float64) {
+	l.observationsMutex.Lock()
+	defer l.observationsMutex.Unlock()
+	if l.histograms == nil {
+		l.histograms = make(map[string]*histogram)
+	}
+	h, ok := l.histograms[name]
+	if !ok {
+		h = &histogram{}
+		l.histograms[name] = h
+	}
+	h.add(value)
+}
+
+// FlushObservations logs a summary line for each name observed since the last
+// flush, in name order, and starts afresh. The lines are subject to the global
+// minimum level, sampling and Close like any INFO line.
+// It's thread-safe.
+func (l *Logger) FlushObservations() {
+	l.observationsMutex.Lock()
+	histograms := l.histograms
+	l.histograms = nil
+	l.observationsMutex.Unlock()
+
+	names := make([]string, 0, len(histograms))
+	for name := range histograms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !l.admits(INFO) {
+			continue
+		}
+		if entry, ok := l.newEntry(time.Time{}, INFO, name, histograms[name].summary()...); ok {
+			l.write(entry)
+		}
+	}
+}
+
+// SetObservationInterval calls FlushObservations every interval in the background.
+// An interval of 0 (the default) stops the periodic flush. Close stops it too,
+// after a final flush, as does closing the Logger this one was derived from.
+// It's thread-safe.
+func (l *Logger) SetObservationInterval(interval time.Duration) {
+	l.observationsMutex.Lock()
+	defer l.observationsMutex.Unlock()
+	if l.observeStop != nil {
+		close(l.observeStop)
+		l.observeStop = nil
+	}
+	l.trackObserver(interval > 0)
+	if interval <= 0 {
+		return
+	}
+	stop := make(chan struct{})
+	l.observeStop = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				l.FlushObservations()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// trackObserver records on the owner whether l, if derived, has a periodic
+// flush running, so the owner's Close can stop it.
+func (l *Logger) trackObserver(running bool) {
+	if l.root == nil {
+		return
+	}
+	owner := l.root
+	owner.observersMutex.Lock()
+	defer owner.observersMutex.Unlock()
+	if !running {
+		delete(owner.observers, l)
+		return
+	}
+	if owner.observers == nil {
+		owner.observers = make(map[*Logger]struct{})
+	}
+	owner.observers[l] = struct{}{}
+}
+
+// stopDerivedObservers stops the periodic flush of every Logger derived from l
+// that has one, after a final flush.
+func (l *Logger) stopDerivedObservers() {
+	l.observersMutex.Lock()
+	observers := make([]*Logger, 0, len(l.observers))
+	for observer := range l.observers {
+		observers = append(observers, observer)
+	}
+	l.observersMutex.Unlock()
+	for _, observer := range observers {
+		observer.SetObservationInterval(0)
+		observer.FlushObservations()
+	}
+}
@@ -0,0 +1,130 @@
+package slog
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestLoggerObserve ensures many samples are summarised as a single line with the
+// exact count, min and max and approximately right percentiles.
+func TestLoggerObserve(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	logger, sink := newCaptureLogger("API")
+
+	// 1..10000 in a scrambled order, so the reservoir sees values from the whole range.
+	const n = 10000
+	for i := 0; i < n; i++ {
+		logger.Observe("latency_ms", float64((i*7919)%n+1))
+	}
+	logger.Observe("queue_depth", 3)
+	logger.FlushObservations()
+
+	entries := sink.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected one summary line per name, got %d", len(entries))
+	}
+	if entries[0].Message != "latency_ms" || entries[1].Message != "queue_depth" {
+		t.Errorf("Expected summaries in name order, got %q and %q", entries[0].Message, entries[1].Message)
+	}
+
+	summary := make(map[string]interface{})
+	for _, field := range entries[0].Fields {
+		summary[field.Key] = field.Value
+	}
+	if summary["count"] != uint64(n) || summary["min"] != 1.0 || summary["max"] != float64(n) {
+		t.Errorf("Expected count=%d min=1 max=%d, got %v", n, n, entries[0].Fields)
+	}
+	for _, p := range []struct {
+		key      string
+		expected float64
+	}{{"p50", 5000}, {"p90", 9000}, {"p99", 9900}} {
+		value := summary[p.key].(float64)
+		if math.Abs(value-p.expected) > n*0.05 {
+			t.Errorf("Expected %s to be about %v, got %v", p.key, p.expected, value)
+		}
+	}
+
+	// Observations start afresh after a flush.
+	logger.FlushObservations()
+	if entries := sink.Entries(); len(entries) != 2 {
+		t.Errorf("Expected no new lines from an empty flush, got %d", len(entries)-2)
+	}
+}
+
+// TestLoggerObservationInterval ensures observations are flushed periodically in
+// the background, and that Close stops the flusher after a final flush.
+func TestLoggerObservationInterval(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	logger, sink := newCaptureLogger("API")
+	logger.SetObservationInterval(10 * time.Millisecond)
+	logger.Observe("latency_ms", 1)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(sink.Entries()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(sink.Entries()) != 1 {
+		t.Fatalf("Expected the periodic flush to log a summary")
+	}
+
+	logger.Observe("latency_ms", 2)
+	logger.Close()
+	if entries := sink.Entries(); len(entries) != 2 {
+		t.Fatalf("Expected Close to flush the pending observation, got %d lines", len(entries))
+	}
+	logger.observationsMutex.Lock()
+	stopped := logger.observeStop == nil
+	logger.observationsMutex.Unlock()
+	if !stopped {
+		t.Errorf("Expected Close to stop the periodic flush")
+	}
+}
+
+// TestLoggerObservationsDerivedClose ensures closing a Logger stops the periodic
+// flush of the Loggers derived from it, after a final flush, and that nothing is
+// flushed once it's closed.
+func TestLoggerObservationsDerivedClose(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+	errs := recordErrors(t)
+
+	logger, sink := newCaptureLogger("API")
+	child := logger.WithFields(Field{Key: "route", Value: "/orders"})
+	child.SetObservationInterval(time.Hour)
+	child.Observe("latency_ms", 1)
+	logger.Close()
+
+	entries := sink.Entries()
+	if len(entries) != 1 || entries[0].Message != "latency_ms" {
+		t.Fatalf("Expected Close to flush the derived Logger's observation, got %v", entries)
+	}
+	child.observationsMutex.Lock()
+	stopped := child.observeStop == nil
+	child.observationsMutex.Unlock()
+	if !stopped {
+		t.Errorf("Expected Close to stop the derived Logger's periodic flush")
+	}
+
+	child.Observe("latency_ms", 2)
+	child.FlushObservations()
+	if n := len(sink.Entries()); n != 1 {
+		t.Errorf("Expected nothing flushed after Close, got %d lines", n)
+	}
+	if reported := errs.Errors(); len(reported) != 1 {
+		t.Errorf("Expected the dropped summary to be reported, got %v", reported)
+	}
+}
@@ -0,0 +1,60 @@
+package slog
+
+import (
+	"time"
+)
+
+// Op is an operation in progress, started with Operation.
+type Op struct {
+	logger *Logger
+	name   string
+	start  time.Time
+	fields []Field // operation and op_id, shared by the start and completion lines
+}
+
+// Operation logs the start of the operation name at DEBUG and returns a handle
+// to log its outcome with, e.g.
+//
+//	op := logger.Operation("import_orders")
+//	if err := importOrders(); err != nil {
+//		op.Failure(err)
+//		return err
+//	}
+//	op.Success(slog.Field{Key: "orders", Value: n})
+//
+// Both lines carry operation=<name> and the same unique op_id, so they can be
+// correlated; the completion line adds duration_ms. Call Success or Failure once.
+func (l *Logger) Operation(name string) *Op {
+	op := &Op{
+		logger: l,
+		name:   name,
+		start:  now(),
+		fields: []Field{
+			{Key: "operation", Value: name},
+			{Key: "op_id", Value: eventIDs.next()},
+		},
+	}
+	op.logger.logFields(DEBUG, "Starting "+name, op.fields...)
+	return op
+}
+
+// Success logs that the operation succeeded at INFO, with fields after the
+// operation's own.
+func (op *Op) Success(fields ...Field) {
+	op.logger.logFields(INFO, op.name+" succeeded", op.completionFields(fields)...)
+}
+
+// Failure logs that the operation failed with err at ERROR, with fields after the
+// operation's own and the error.
+func (op *Op) Failure(err error, fields ...Field) {
+	fields = append([]Field{{Key: "error", Value: err}}, fields...)
+	op.logger.logFields(ERROR, op.name+" failed", op.completionFields(fields)...)
+}
+
+// completionFields returns the operation's fields, its duration so far and then fields.
+func (op *Op) completionFields(fields []Field) []Field {
+	completion := make([]Field, 0, len(op.fields)+1+len(fields))
+	completion = append(completion, op.fields...)
+	completion = append(completion, Field{Key: "duration_ms", Value: now().Sub(op.start).Milliseconds()})
+	return append(completion, fields...)
+}
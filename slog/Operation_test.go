@@ -0,0 +1,85 @@
+package slog
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fieldMap indexes an entry's fields by key.
+func fieldMap(entry Entry) map[string]interface{} {
+	fields := make(map[string]interface{}, len(entry.Fields))
+	for _, field := range entry.Fields {
+		fields[field.Key] = field.Value
+	}
+	return fields
+}
+
+// TestLoggerOperation ensures the start and completion lines share the operation
+// and op_id fields, the completion carries the measured duration, and the level
+// depends on the outcome.
+func TestLoggerOperation(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetClock(nil)
+	})
+	SetGlobalMinLevel(DEBUG)
+	clock := &fakeClock{current: time.Date(2022, time.July, 4, 10, 0, 0, 0, time.UTC)}
+	SetClock(clock)
+
+	logger, sink := newCaptureLogger("Jobs")
+
+	op := logger.Operation("import_orders")
+	clock.Advance(1500 * time.Millisecond)
+	op.Success(Field{Key: "orders", Value: 12})
+
+	failed := logger.Operation("send_email")
+	clock.Advance(20 * time.Millisecond)
+	failed.Failure(errors.New("smtp timeout"))
+
+	entries := sink.Entries()
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(entries))
+	}
+	testCases := []struct {
+		level     LogLevel
+		message   string
+		operation string
+	}{
+		{DEBUG, "Starting import_orders", "import_orders"},
+		{INFO, "import_orders succeeded", "import_orders"},
+		{DEBUG, "Starting send_email", "send_email"},
+		{ERROR, "send_email failed", "send_email"},
+	}
+	for i, tc := range testCases {
+		if entries[i].Level != tc.level || entries[i].Message != tc.message {
+			t.Errorf("Entry %d: expected %s %q, got %s %q", i, tc.level, tc.message, entries[i].Level, entries[i].Message)
+		}
+		if operation := fieldMap(entries[i])["operation"]; operation != tc.operation {
+			t.Errorf("Entry %d: expected operation=%s, got %v", i, tc.operation, operation)
+		}
+	}
+
+	start, success := fieldMap(entries[0]), fieldMap(entries[1])
+	if start["op_id"] == nil || start["op_id"] != success["op_id"] {
+		t.Errorf("Expected the start and completion to share an op_id, got %v and %v", start["op_id"], success["op_id"])
+	}
+	if start["op_id"] == fieldMap(entries[2])["op_id"] {
+		t.Errorf("Expected each operation to get its own op_id")
+	}
+	if _, ok := start["duration_ms"]; ok {
+		t.Errorf("Expected no duration on the start line")
+	}
+	if success["duration_ms"] != int64(1500) || success["orders"] != 12 {
+		t.Errorf("Expected duration_ms=1500 and orders=12, got %v", entries[1].Fields)
+	}
+
+	failure := fieldMap(entries[3])
+	if failure["duration_ms"] != int64(20) {
+		t.Errorf("Expected duration_ms=20, got %v", failure["duration_ms"])
+	}
+	if err, ok := failure["error"].(error); !ok || err.Error() != "smtp timeout" {
+		t.Errorf("Expected the error field, got %v", failure["error"])
+	}
+}
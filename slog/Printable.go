@@ -0,0 +1,65 @@
+package slog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// fmtPanicMarker is how fmt renders a value whose String, Error or Format method panicked.
+const fmtPanicMarker = "%!v(PANIC="
+
+// unprintable is the placeholder for a value that can't be formatted.
+func unprintable(value interface{}) string {
+	return fmt.Sprintf("<unprintable: %T>", value)
+}
+
+// printable formats value with fmt.Sprint, substituting a placeholder and
+// reporting to the error handler if a String, Error or Format method panics, so
+// a misbehaving type can't garble or break the line.
+func printable(value interface{}) string {
+	text := fmt.Sprint(value)
+	if !strings.Contains(text, fmtPanicMarker) {
+		return text
+	}
+	reportError(fmt.Errorf("formatting a %T value panicked: %s", value, text))
+	return unprintable(value)
+}
+
+// sprintfPrintable is fmt.Sprintf, except that params whose formatting panics
+// are replaced by a placeholder (see printable).
+func sprintfPrintable(msg string, params ...interface{}) string {
+	message := fmt.Sprintf(msg, params...)
+	if !strings.Contains(message, "(PANIC=") {
+		return message
+	}
+	safe := make([]interface{}, len(params))
+	for i, param := range params {
+		safe[i] = param
+		if strings.Contains(fmt.Sprint(param), fmtPanicMarker) {
+			safe[i] = printable(param)
+		}
+	}
+	return fmt.Sprintf(msg, safe...)
+}
+
+// marshalPrintable encodes value as JSON for structured outputs. Joined errors
+// become arrays (see Errs), and a value that can't be marshalled (e.g. a channel)
+// is encoded as its text instead. If a MarshalJSON, Error or String method panics
+// the placeholder is encoded instead and the panic reported, as with printable.
+func marshalPrintable(value interface{}) (encoded []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			reportError(fmt.Errorf("encoding a %T value panicked: %v", value, r))
+			encoded, _ = json.Marshal(unprintable(value))
+		}
+	}()
+	if list, ok := asErrorList(value); ok {
+		value = list
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		encoded, _ = json.Marshal(formatFieldValue(value))
+	}
+	return encoded
+}
@@ -0,0 +1,142 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// panickyStringer is a custom type whose String method panics.
+type panickyStringer struct{}
+
+func (*panickyStringer) String() string {
+	panic("boom")
+}
+
+// TestUnprintableValues ensures values whose formatting panics are replaced by a
+// placeholder, in fields and message parameters alike, and reported to the error handler.
+func TestUnprintableValues(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	recorder := recordErrors(t)
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Orders").WithFields(
+		Field{Key: "order", Value: &panickyStringer{}},
+		Field{Key: "lazy", Value: Lazy(func() interface{} { panic("lazy boom") })},
+		Field{Key: "ok", Value: 1},
+	)
+
+	logger.Info("Processing %v for %s", &panickyStringer{}, "alice")
+
+	expected := "[INFO][Orders] Processing <unprintable: *slog.panickyStringer> for alice " +
+		"order=<unprintable: *slog.panickyStringer> lazy=<unprintable: slog.LazyValue> ok=1\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	errs := recorder.Errors()
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 reported errors, got %v", errs)
+	}
+	for _, err := range errs {
+		if !strings.Contains(err.Error(), "boom") {
+			t.Errorf("Expected the report to include the panic, got %v", err)
+		}
+	}
+}
+
+// panickyError is a custom error whose Error method panics.
+type panickyError struct{}
+
+func (*panickyError) Error() string {
+	panic("error boom")
+}
+
+// TestUnprintableErrs ensures an error whose Error method panics is replaced by
+// a placeholder inside an Errs field, in text and JSON output alike.
+func TestUnprintableErrs(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetClock(nil)
+	})
+	SetGlobalMinLevel(INFO)
+	SetClock(&fakeClock{current: time.Date(2022, time.July, 4, 10, 20, 30, 0, time.UTC)})
+
+	recorder := recordErrors(t)
+	var stdout, stderr bytes.Buffer
+	logger := newNDJSONLogger("Import", &stdout, &stderr, true)
+	logger.timestamps = false
+	logger.WithFields(Errs("failures", []error{errors.New("row 3 invalid"), &panickyError{}})).Error("Import finished")
+
+	expectedText := "[ERROR][Import] Import finished failures=[1) row 3 invalid (*errors.errorString); " +
+		"2) <unprintable: *slog.panickyError> (*slog.panickyError)]\n"
+	if stderr.String() != expectedText {
+		t.Errorf("Expected %q, got %q", expectedText, stderr.String())
+	}
+	expectedJSON := `{"time":"2022-07-04T10:20:30Z","level":"ERROR","component":"Import","message":"Import finished",` +
+		`"fields":{"failures":[{"message":"row 3 invalid","type":"*errors.errorString"},` +
+		`{"message":"\u003cunprintable: *slog.panickyError\u003e","type":"*slog.panickyError"}]}}` + "\n"
+	if stdout.String() != expectedJSON {
+		t.Errorf("Expected %q, got %q", expectedJSON, stdout.String())
+	}
+	errs := recorder.Errors()
+	if len(errs) == 0 {
+		t.Fatal("Expected the panic to be reported")
+	}
+	for _, err := range errs {
+		if !strings.Contains(err.Error(), "error boom") {
+			t.Errorf("Expected the report to include the panic, got %v", err)
+		}
+	}
+}
+
+// panickyMarshaler is a custom type whose MarshalJSON method panics.
+type panickyMarshaler struct{}
+
+func (panickyMarshaler) MarshalJSON() ([]byte, error) {
+	panic("marshal boom")
+}
+
+// TestUnprintableValuesJSON ensures values whose encoding panics are replaced by
+// a placeholder in JSON output too, and reported, rather than crashing the call.
+func TestUnprintableValuesJSON(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetClock(nil)
+	})
+	SetGlobalMinLevel(INFO)
+	SetClock(&fakeClock{current: time.Date(2022, time.July, 4, 10, 20, 30, 0, time.UTC)})
+
+	recorder := recordErrors(t)
+	var stdout, stderr bytes.Buffer
+	logger := newNDJSONLogger("Orders", &stdout, &stderr, false).WithFields(
+		Field{Key: "lazy", Value: Lazy(func() interface{} { panic("lazy boom") })},
+		Field{Key: "custom", Value: panickyMarshaler{}},
+		Field{Key: "ok", Value: 1},
+	)
+
+	logger.Info("Processing")
+
+	expected := `{"time":"2022-07-04T10:20:30Z","level":"INFO","component":"Orders","message":"Processing",` +
+		`"fields":{"custom":"\u003cunprintable: slog.panickyMarshaler\u003e","lazy":"\u003cunprintable: slog.LazyValue\u003e","ok":1}}` + "\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+	errs := recorder.Errors()
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 reported errors, got %v", errs)
+	}
+	for _, err := range errs {
+		if !strings.Contains(err.Error(), "boom") {
+			t.Errorf("Expected the report to include the panic, got %v", err)
+		}
+	}
+}
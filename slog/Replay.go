@@ -0,0 +1,105 @@
+package slog
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// ReplayTimed re-emits the lines of a recorded log through target, sleeping between
+// lines in proportion to the gaps between their original timestamps.
+//
+// speed scales the playback: 1 is real time, 2 is double speed, and 0 (or less)
+// replays as fast as possible. Each line keeps its original level and component
+// (unless SetGoroutineComponent overrides it); the rest of the line, fields
+// included, becomes the message. Otherwise lines go through target like any other
+// log call: the global minimum level, sampling, suppressions and the rest of its
+// configuration apply, and its own fields are added. Lines whose timestamp can't
+// be parsed (e.g. from a logger without timestamps) are re-emitted without a
+// delay, and lines without a level prefix are re-emitted as INFO.
+func ReplayTimed(r io.Reader, target *Logger, speed float64) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024) // Allow long records
+	var previous time.Time
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		timestamp, record := parseReplayTimestamp(line)
+		if !timestamp.IsZero() {
+			if speed > 0 && !previous.IsZero() && timestamp.After(previous) {
+				time.Sleep(time.Duration(float64(timestamp.Sub(previous)) / speed))
+			}
+			previous = timestamp
+		}
+
+		level, component, message := parseReplayRecord(record)
+		if !target.admits(level) {
+			continue
+		}
+		entry, ok := target.newEntry(time.Time{}, level, message)
+		if !ok {
+			continue
+		}
+		if _, overridden := goroutineComponents.get(); !overridden {
+			entry.Component = component
+		}
+		target.write(entry)
+	}
+	return scanner.Err()
+}
+
+// parseReplayTimestamp splits a line into its leading timestamp, in the layout
+// written by Logger at any TimeResolution, and the rest of the line.
+// If there is no timestamp it returns the zero time and the whole line.
+func parseReplayTimestamp(line string) (time.Time, string) {
+	parts := strings.SplitN(line, " ", 3)
+	if len(parts) < 3 {
+		return time.Time{}, line
+	}
+	timestamp, err := time.ParseInLocation(timestampLayout, parts[0]+" "+parts[1], time.Local)
+	if err != nil {
+		return time.Time{}, line
+	}
+	return timestamp, parts[2]
+}
+
+// parseReplayRecord splits "[LEVEL][COMPONENT] message" into its parts. The
+// component is optional; a record without a known level prefix is INFO.
+func parseReplayRecord(record string) (LogLevel, string, string) {
+	level, rest, ok := cutBracketed(record)
+	parsedLevel, known := parseLogLevel(level)
+	if !ok || !known {
+		return INFO, "", record
+	}
+	component := ""
+	if c, afterComponent, ok := cutBracketed(rest); ok {
+		component, rest = c, afterComponent
+	}
+	return parsedLevel, component, strings.TrimPrefix(rest, " ")
+}
+
+// cutBracketed returns the text inside a leading "[...]" and what follows it.
+func cutBracketed(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, "[") {
+		return "", s, false
+	}
+	end := strings.Index(s, "]")
+	if end < 0 {
+		return "", s, false
+	}
+	return s[1:end], s[end+1:], true
+}
+
+// parseLogLevel returns the LogLevel whose String() is name.
+func parseLogLevel(name string) (LogLevel, bool) {
+	for level := ERROR; level <= FINE; level++ {
+		if level.String() == name {
+			return level, true
+		}
+	}
+	return INFO, false
+}
@@ -0,0 +1,111 @@
+package slog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestReplayTimed ensures recorded lines are re-emitted in order with their level and
+// component, and that the gaps between them are honoured according to the speed.
+func TestReplayTimed(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(DEBUG)
+
+	start := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.Local)
+	stamp := func(offset time.Duration) string {
+		return start.Add(offset).Format(timestampLayout)
+	}
+	// Gaps of 1s and 2s: 3s of recorded time in total.
+	recording := strings.Join([]string{
+		stamp(0) + " [INFO][Orders] Order 1 placed",
+		stamp(1*time.Second) + " [WARN] Stock low for 100% of items",
+		stamp(1*time.Second) + " [FINE][Orders] Filtered by the global level",
+		stamp(3*time.Second) + " [ERROR][Orders] Order 1 failed code=7",
+		"not a log line",
+	}, "\n")
+
+	expected := []string{
+		"[INFO][Orders] Order 1 placed",
+		"[WARN] Stock low for 100% of items",
+		"[ERROR][Orders] Order 1 failed code=7",
+		"[INFO] not a log line",
+	}
+
+	testCases := []struct {
+		name       string
+		speed      float64
+		minElapsed time.Duration
+		maxElapsed time.Duration
+	}{
+		{"AsFastAsPossible", 0, 0, 100 * time.Millisecond},
+		{"TwentyTimesSpeed", 20, 140 * time.Millisecond, 1 * time.Second}, // 3s / 20 = 150ms
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			target := newTestLogger(&buf, "Ignored")
+
+			begin := time.Now()
+			if err := ReplayTimed(strings.NewReader(recording), target, tc.speed); err != nil {
+				t.Fatalf("Expected replay to succeed, got %v", err)
+			}
+			elapsed := time.Since(begin)
+
+			if elapsed < tc.minElapsed || elapsed > tc.maxElapsed {
+				t.Errorf("Expected replay to take between %s and %s, took %s", tc.minElapsed, tc.maxElapsed, elapsed)
+			}
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+				t.Errorf("Expected replayed lines:\n%s\nGot:\n%s", strings.Join(expected, "\n"), strings.Join(lines, "\n"))
+			}
+		})
+	}
+}
+
+// TestReplayTimedAppliesTargetConfig ensures replayed lines go through the
+// target's configuration like any other log call.
+func TestReplayTimedAppliesTargetConfig(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	recording := strings.Join([]string{
+		"[INFO][Web] GET /healthz 200",
+		"[INFO][Web] GET /orders 200",
+		"[WARN][Web] Slow response",
+	}, "\n")
+
+	var buf bytes.Buffer
+	target := newTestLogger(&buf, "Ignored").WithFields(Field{Key: "replayed", Value: true})
+	target.AddSuppressionPattern("healthz")
+	SetGoroutineComponent("Replay")
+	defer ClearGoroutineComponent()
+	if err := ReplayTimed(strings.NewReader(recording), target, 0); err != nil {
+		t.Fatalf("Expected replay to succeed, got %v", err)
+	}
+
+	expected := "[INFO][Replay] GET /orders 200 replayed=true\n[WARN][Replay] Slow response replayed=true\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	// Lines after Close are dropped as configured, not written to the closed output.
+	errs := recordErrors(t)
+	buf.Reset()
+	target.Close()
+	ReplayTimed(strings.NewReader(recording), target, 0)
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing written after Close, got %q", buf.String())
+	}
+	if len(errs.Errors()) != 3 {
+		t.Errorf("Expected the 3 lines to be reported, got %v", errs.Errors())
+	}
+}
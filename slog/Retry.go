@@ -0,0 +1,59 @@
+package slog
+
+import "fmt"
+
+// Retry tracks the attempts of a retried operation, started with RetryLogger.
+type Retry struct {
+	logger *Logger
+	name   string
+	fields []Field // operation and retry_id, shared by every line of the retry loop
+}
+
+// RetryLogger returns a handle to log the attempts of the retried operation name, e.g.
+//
+//	retry := logger.RetryLogger("fetch_rates")
+//	for attempt := 1; attempt <= 3; attempt++ {
+//		if err = fetchRates(); err == nil {
+//			retry.Succeeded(attempt)
+//			return nil
+//		}
+//		retry.Attempt(attempt, err)
+//	}
+//	retry.Exhausted(3, err)
+//
+// Every line carries operation=<name> and the same unique retry_id, so the whole
+// loop can be correlated. Nothing is logged until the first call.
+func (l *Logger) RetryLogger(operation string) *Retry {
+	return &Retry{
+		logger: l,
+		name:   operation,
+		fields: []Field{
+			{Key: "operation", Value: operation},
+			{Key: "retry_id", Value: eventIDs.next()},
+		},
+	}
+}
+
+// Attempt logs that attempt n (counting from 1) failed with err, at WARN.
+func (r *Retry) Attempt(n int, err error) {
+	r.logger.logFields(WARN, fmt.Sprintf("%s attempt %d failed", r.name, n),
+		r.withFields(Field{Key: "attempt", Value: n}, Field{Key: "error", Value: err})...)
+}
+
+// Succeeded logs that the operation succeeded on attempt afterAttempts, at INFO.
+func (r *Retry) Succeeded(afterAttempts int) {
+	r.logger.logFields(INFO, fmt.Sprintf("%s succeeded after %d attempts", r.name, afterAttempts),
+		r.withFields(Field{Key: "attempts", Value: afterAttempts})...)
+}
+
+// Exhausted logs that the operation was given up on after afterAttempts attempts,
+// the last failing with lastErr, at ERROR.
+func (r *Retry) Exhausted(afterAttempts int, lastErr error) {
+	r.logger.logFields(ERROR, fmt.Sprintf("%s failed after %d attempts", r.name, afterAttempts),
+		r.withFields(Field{Key: "attempts", Value: afterAttempts}, Field{Key: "error", Value: lastErr})...)
+}
+
+// withFields returns the retry's fields followed by fields.
+func (r *Retry) withFields(fields ...Field) []Field {
+	return append(append([]Field(nil), r.fields...), fields...)
+}
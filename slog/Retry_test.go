@@ -0,0 +1,69 @@
+package slog
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestLoggerRetryLogger ensures attempts are numbered and logged at WARN, every
+// line of a retry loop shares its operation and retry_id, and the terminal line's
+// level depends on the outcome.
+func TestLoggerRetryLogger(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	logger, sink := newCaptureLogger("Rates")
+	timeout := errors.New("timeout")
+
+	recovered := logger.RetryLogger("fetch_rates")
+	recovered.Attempt(1, timeout)
+	recovered.Attempt(2, timeout)
+	recovered.Succeeded(3)
+
+	failed := logger.RetryLogger("fetch_rates")
+	failed.Attempt(1, timeout)
+	failed.Exhausted(2, errors.New("connection refused"))
+
+	entries := sink.Entries()
+	testCases := []struct {
+		level   LogLevel
+		message string
+		attempt interface{}
+		count   interface{}
+		err     string
+	}{
+		{WARN, "fetch_rates attempt 1 failed", 1, nil, "timeout"},
+		{WARN, "fetch_rates attempt 2 failed", 2, nil, "timeout"},
+		{INFO, "fetch_rates succeeded after 3 attempts", nil, 3, ""},
+		{WARN, "fetch_rates attempt 1 failed", 1, nil, "timeout"},
+		{ERROR, "fetch_rates failed after 2 attempts", nil, 2, "connection refused"},
+	}
+	if len(entries) != len(testCases) {
+		t.Fatalf("Expected %d entries, got %d", len(testCases), len(entries))
+	}
+	for i, tc := range testCases {
+		fields := fieldMap(entries[i])
+		if entries[i].Level != tc.level || entries[i].Message != tc.message {
+			t.Errorf("Entry %d: expected %s %q, got %s %q", i, tc.level, tc.message, entries[i].Level, entries[i].Message)
+		}
+		if fields["operation"] != "fetch_rates" || fields["attempt"] != tc.attempt || fields["attempts"] != tc.count {
+			t.Errorf("Entry %d: unexpected fields %v", i, entries[i].Fields)
+		}
+		if err, _ := fields["error"].(error); (err == nil && tc.err != "") || (err != nil && err.Error() != tc.err) {
+			t.Errorf("Entry %d: expected error %q, got %v", i, tc.err, fields["error"])
+		}
+	}
+
+	first := fieldMap(entries[0])["retry_id"]
+	for i := 1; i < 3; i++ {
+		if id := fieldMap(entries[i])["retry_id"]; id != first {
+			t.Errorf("Entry %d: expected retry_id %v, got %v", i, first, id)
+		}
+	}
+	if fieldMap(entries[3])["retry_id"] == first {
+		t.Errorf("Expected each retry loop to get its own retry_id")
+	}
+}
@@ -0,0 +1,217 @@
+package slog
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// How the SQLite sink batches its inserts.
+const (
+	sqliteBatchSize        = 100
+	sqliteFlushInterval    = time.Second
+	sqliteMaxQueuedBatches = 8                               // Full batches held while inserting falls behind
+	sqliteTimeLayout       = "2006-01-02 15:04:05.000000000" // UTC, fixed width so it sorts as text
+)
+
+// sqliteTableName restricts table names to plain identifiers, as they can't be bound as parameters.
+var sqliteTableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// NewSQLiteSink creates a Logger that stores each entry as a row of table in db,
+// with the columns ts, level, component, message, fields and meta (JSON objects,
+// the latter holding the metadata added with WithMeta).
+//
+// The table and its indexes on ts and level are created if they don't exist, so
+// reusing a table across runs is safe. db may use any SQLite driver for
+// database/sql. Rows are inserted in batches, one transaction per batch, when a
+// batch fills, every second, and when the Logger is closed. Full batches are
+// inserted in the background, so a slow disk doesn't hold up logging; if inserting
+// falls far behind the oldest batch is dropped and reported to the error handler.
+// Call Close before the process exits so the last batch isn't lost.
+func NewSQLiteSink(db *sql.DB, table string) (*Logger, error) {
+	if !sqliteTableName.MatchString(table) {
+		return nil, fmt.Errorf("sqlite: invalid table name %q", table)
+	}
+	schema := []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (ts TEXT NOT NULL, level TEXT NOT NULL, component TEXT NOT NULL, message TEXT NOT NULL, fields TEXT NOT NULL, meta TEXT NOT NULL)", table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_ts_idx ON %s (ts)", table, table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_level_idx ON %s (level)", table, table),
+	}
+	for _, statement := range schema {
+		if _, err := db.Exec(statement); err != nil {
+			return nil, fmt.Errorf("sqlite: creating schema: %w", err)
+		}
+	}
+
+	sink := &sqliteWriter{
+		db:     db,
+		insert: fmt.Sprintf("INSERT INTO %s (ts, level, component, message, fields, meta) VALUES (?, ?, ?, ?, ?, ?)", table),
+		ready:  make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	go sink.flushPeriodically(sqliteFlushInterval)
+	return &Logger{sink: sink, closer: sink}, nil
+}
+
+// sqliteWriter batches entries and inserts them into the sink's table.
+type sqliteWriter struct {
+	db     *sql.DB
+	insert string
+
+	insertMutex sync.Mutex // Held while inserting, so batches go in in order
+
+	mutex   sync.Mutex // Guards the fields below
+	pending []Entry
+	full    [][]Entry // Batches waiting to be inserted, oldest first
+	closed  bool
+
+	ready chan struct{} // Signals flushPeriodically that a batch is full
+	done  chan struct{} // Closed to stop flushPeriodically
+}
+
+// writeEntry implements entrySink. A batch that fills is handed to the
+// background flush rather than inserted here.
+func (w *sqliteWriter) writeEntry(entry Entry) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.pending = append(w.pending, entry)
+	if len(w.pending) < sqliteBatchSize {
+		return nil
+	}
+	err := w.queueBatchLocked()
+	select {
+	case w.ready <- struct{}{}:
+	default: // Already signalled
+	}
+	return err
+}
+
+// queueBatchLocked moves the pending batch to the insert queue, dropping the
+// oldest queued batch if too many are waiting. The caller must hold w.mutex.
+func (w *sqliteWriter) queueBatchLocked() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	var err error
+	if len(w.full) >= sqliteMaxQueuedBatches {
+		err = fmt.Errorf("sqlite: falling behind, dropped %d entries", len(w.full[0]))
+		w.full = w.full[1:]
+	}
+	w.full = append(w.full, w.pending)
+	w.pending = nil
+	return err
+}
+
+// Flush inserts the pending batch and any queued ones, returning the first error.
+func (w *sqliteWriter) Flush() error {
+	w.mutex.Lock()
+	err := w.queueBatchLocked()
+	w.mutex.Unlock()
+	if insertErr := w.insertQueued(); err == nil {
+		err = insertErr
+	}
+	return err
+}
+
+// Close stops the periodic flush and inserts the pending batch.
+// The database itself is left open, it belongs to the caller.
+func (w *sqliteWriter) Close() error {
+	w.mutex.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.done)
+	}
+	w.mutex.Unlock()
+	return w.Flush()
+}
+
+// flushPeriodically inserts the pending batch every interval, and full batches
+// as soon as they're queued, until Close is called.
+func (w *sqliteWriter) flushPeriodically(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.Flush(); err != nil {
+				reportError(err)
+			}
+		case <-w.ready:
+			if err := w.insertQueued(); err != nil {
+				reportError(err)
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// insertQueued inserts the queued batches in order, returning the first error.
+func (w *sqliteWriter) insertQueued() error {
+	w.insertMutex.Lock()
+	defer w.insertMutex.Unlock()
+	var firstErr error
+	for {
+		w.mutex.Lock()
+		if len(w.full) == 0 {
+			w.mutex.Unlock()
+			return firstErr
+		}
+		batch := w.full[0]
+		w.full = w.full[1:]
+		w.mutex.Unlock()
+
+		if err := w.insertBatch(batch); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+}
+
+// insertBatch inserts batch in a single transaction. The batch is dropped if the
+// insert fails, so a broken database can't make the queue grow without bound.
+// The caller must hold w.insertMutex.
+func (w *sqliteWriter) insertBatch(batch []Entry) error {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return fmt.Errorf("sqlite: dropped %d entries: %w", len(batch), err)
+	}
+	stmt, err := tx.Prepare(w.insert)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("sqlite: dropped %d entries: %w", len(batch), err)
+	}
+	defer stmt.Close()
+
+	for _, entry := range batch {
+		_, err := stmt.Exec(
+			entry.Time.UTC().Format(sqliteTimeLayout),
+			entry.Level.String(),
+			entry.Component,
+			entry.Message,
+			fieldsJSON(entry.Fields),
+			fieldsJSON(entry.Meta),
+		)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("sqlite: dropped %d entries: %w", len(batch), err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("sqlite: dropped %d entries: %w", len(batch), err)
+	}
+	return nil
+}
+
+// fieldsJSON renders fields as a JSON object, encoding each value with
+// marshalPrintable.
+func fieldsJSON(fields []Field) string {
+	object := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		object[field.Key] = marshalPrintable(field.Value)
+	}
+	encoded, _ := json.Marshal(object)
+	return string(encoded)
+}
@@ -0,0 +1,269 @@
+package slog
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSQLDatabase is the in-memory state behind a fakeSQLDriver connection. It
+// understands just enough SQL for the sink: schema statements are recorded,
+// INSERTs store a row, and "SELECT message, fields ... WHERE level = ?" reads rows back.
+type fakeSQLDatabase struct {
+	mutex      sync.Mutex
+	statements []string
+	rows       [][]driver.Value
+	commits    int
+	block      chan struct{} // If set, INSERTs wait until it's closed
+}
+
+// fakeSQLDriver is a database/sql driver backed by named fakeSQLDatabases, so the
+// sink can be tested without a real SQLite driver.
+type fakeSQLDriver struct {
+	mutex     sync.Mutex
+	databases map[string]*fakeSQLDatabase
+}
+
+var fakeSQL = &fakeSQLDriver{databases: make(map[string]*fakeSQLDatabase)}
+
+func init() {
+	sql.Register("slogfakesql", fakeSQL)
+}
+
+// openFakeSQL opens a fresh fake database for a test.
+func openFakeSQL(t *testing.T) (*sql.DB, *fakeSQLDatabase) {
+	state := &fakeSQLDatabase{}
+	fakeSQL.mutex.Lock()
+	fakeSQL.databases[t.Name()] = state
+	fakeSQL.mutex.Unlock()
+
+	db, err := sql.Open("slogfakesql", t.Name())
+	if err != nil {
+		t.Fatalf("Failed to open fake database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, state
+}
+
+func (d *fakeSQLDriver) Open(name string) (driver.Conn, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	state, ok := d.databases[name]
+	if !ok {
+		return nil, errors.New("no such fake database")
+	}
+	return &fakeSQLConn{state: state}, nil
+}
+
+type fakeSQLConn struct {
+	state *fakeSQLDatabase
+}
+
+func (c *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSQLStmt{state: c.state, query: query}, nil
+}
+func (c *fakeSQLConn) Close() error              { return nil }
+func (c *fakeSQLConn) Begin() (driver.Tx, error) { return &fakeSQLTx{state: c.state}, nil }
+
+type fakeSQLTx struct {
+	state *fakeSQLDatabase
+}
+
+func (tx *fakeSQLTx) Commit() error {
+	tx.state.mutex.Lock()
+	defer tx.state.mutex.Unlock()
+	tx.state.commits++
+	return nil
+}
+func (tx *fakeSQLTx) Rollback() error { return nil }
+
+type fakeSQLStmt struct {
+	state *fakeSQLDatabase
+	query string
+}
+
+func (s *fakeSQLStmt) Close() error  { return nil }
+func (s *fakeSQLStmt) NumInput() int { return -1 }
+
+func (s *fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.state.block != nil && strings.HasPrefix(s.query, "INSERT") {
+		<-s.state.block
+	}
+	s.state.mutex.Lock()
+	defer s.state.mutex.Unlock()
+	if strings.HasPrefix(s.query, "INSERT") {
+		s.state.rows = append(s.state.rows, args)
+	} else {
+		s.state.statements = append(s.state.statements, s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.state.mutex.Lock()
+	defer s.state.mutex.Unlock()
+	rows := &fakeSQLRows{}
+	for _, row := range s.state.rows {
+		if row[1] == args[0] { // level = ?
+			rows.values = append(rows.values, []driver.Value{row[3], row[4]})
+		}
+	}
+	return rows, nil
+}
+
+type fakeSQLRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeSQLRows) Columns() []string { return []string{"message", "fields"} }
+func (r *fakeSQLRows) Close() error      { return nil }
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// TestSQLiteSink ensures the schema is created idempotently and that records are
+// inserted in transactional batches and can be queried back by level.
+func TestSQLiteSink(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	db, state := openFakeSQL(t)
+
+	// Creating a second sink on the same table only issues IF NOT EXISTS statements.
+	for i := 0; i < 2; i++ {
+		sink, err := NewSQLiteSink(db, "app_logs")
+		if err != nil {
+			t.Fatalf("Expected NewSQLiteSink to succeed, got %v", err)
+		}
+		sink.Close()
+	}
+	if len(state.statements) != 6 {
+		t.Fatalf("Expected 3 schema statements per sink, got %d", len(state.statements))
+	}
+	for _, statement := range state.statements {
+		if !strings.Contains(statement, "IF NOT EXISTS") {
+			t.Errorf("Expected schema statement to be idempotent, got %q", statement)
+		}
+	}
+
+	sink, err := NewSQLiteSink(db, "app_logs")
+	if err != nil {
+		t.Fatalf("Expected NewSQLiteSink to succeed, got %v", err)
+	}
+	sink.component = "Desktop"
+	logger := sink.WithFieldIf(ERROR, "user", func() interface{} { return "alice" }).WithMeta("account_id", "acct-9")
+
+	for i := 0; i < sqliteBatchSize; i++ {
+		logger.Info("Opened document %d", i)
+	}
+	logger.Error("Save failed")
+	logger.Debug("Filtered out")
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Expected Close to succeed, got %v", err)
+	}
+
+	// One full batch, then the remaining entry on Close.
+	if state.commits != 2 {
+		t.Errorf("Expected 2 committed batches, got %d", state.commits)
+	}
+
+	rows, err := db.Query("SELECT message, fields FROM app_logs WHERE level = ?", "ERROR")
+	if err != nil {
+		t.Fatalf("Failed to query rows: %v", err)
+	}
+	defer rows.Close()
+	var messages []string
+	for rows.Next() {
+		var message, fields string
+		if err := rows.Scan(&message, &fields); err != nil {
+			t.Fatalf("Failed to scan row: %v", err)
+		}
+		messages = append(messages, message)
+
+		var decoded map[string]string
+		if err := json.Unmarshal([]byte(fields), &decoded); err != nil || decoded["user"] != "alice" {
+			t.Errorf("Expected fields to be a JSON object with user=alice, got %q", fields)
+		}
+	}
+	if len(messages) != 1 || messages[0] != "Save failed" {
+		t.Errorf("Expected a single ERROR row \"Save failed\", got %q", messages)
+	}
+	if n := len(state.rows); n != sqliteBatchSize+1 {
+		t.Errorf("Expected %d rows in total, got %d", sqliteBatchSize+1, n)
+	}
+	if component := state.rows[0][2]; component != "Desktop" {
+		t.Errorf("Expected the component column to be Desktop, got %v", component)
+	}
+	if meta := state.rows[0][5]; meta != `{"account_id":"acct-9"}` {
+		t.Errorf("Expected the meta column to hold the metadata, got %v", meta)
+	}
+	if fields := state.rows[0][4]; strings.Contains(fields.(string), "acct-9") {
+		t.Errorf("Expected the metadata to stay out of the fields column, got %v", fields)
+	}
+}
+
+// TestSQLiteSinkSlowDatabase ensures a slow insert doesn't hold up logging, and
+// that the queued batches are inserted on Close.
+func TestSQLiteSinkSlowDatabase(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	db, state := openFakeSQL(t)
+	sink, err := NewSQLiteSink(db, "app_logs")
+	if err != nil {
+		t.Fatalf("Expected NewSQLiteSink to succeed, got %v", err)
+	}
+	state.block = make(chan struct{})
+
+	logged := make(chan struct{})
+	go func() {
+		defer close(logged)
+		for i := 0; i < 3*sqliteBatchSize; i++ {
+			sink.Info("Opened document %d", i)
+		}
+	}()
+	select {
+	case <-logged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected logging not to wait for the database")
+	}
+
+	close(state.block)
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Expected Close to succeed, got %v", err)
+	}
+	if n := len(state.rows); n != 3*sqliteBatchSize {
+		t.Errorf("Expected %d rows in total, got %d", 3*sqliteBatchSize, n)
+	}
+	if message := state.rows[0][3]; message != "Opened document 0" {
+		t.Errorf("Expected the batches inserted in order, got %v first", message)
+	}
+}
+
+// TestSQLiteSinkInvalidTable ensures table names that aren't plain identifiers are rejected.
+func TestSQLiteSinkInvalidTable(t *testing.T) {
+	db, state := openFakeSQL(t)
+	if _, err := NewSQLiteSink(db, "logs; DROP TABLE users"); err == nil {
+		t.Errorf("Expected an error for an invalid table name")
+	}
+	if len(state.statements) != 0 {
+		t.Errorf("Expected no statements for an invalid table name, got %q", state.statements)
+	}
+}
@@ -0,0 +1,39 @@
+package slog
+
+import "sync/atomic"
+
+// SetLevelSampling keeps only 1 in N lines for each level in rates, e.g.
+//
+//	logger.SetLevelSampling(map[slog.LogLevel]int{slog.INFO: 10, slog.FINE: 100})
+//
+// keeps every 10th INFO line and every 100th FINE line, starting with the first.
+// Levels missing from rates, or with N <= 1, keep every line, so leaving ERROR out
+// exempts it from sampling entirely. Sampling applies after level filtering, so
+// lines dropped by the global level don't count towards N. Passing nil turns
+// sampling off. The map is copied, later changes to it have no effect.
+// It's thread-safe.
+func (l *Logger) SetLevelSampling(rates map[LogLevel]int) {
+	var copied map[LogLevel]int
+	if len(rates) > 0 {
+		copied = make(map[LogLevel]int, len(rates))
+		for level, n := range rates {
+			copied[level] = n
+		}
+	}
+
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.sampling = copied
+}
+
+// isSampled reports whether the sampler keeps the next line at level.
+func (l *Logger) isSampled(level LogLevel) bool {
+	l.settingsMutex.RLock()
+	n := l.sampling[level]
+	l.settingsMutex.RUnlock()
+	if n <= 1 || level < ERROR || level > FINE {
+		return true
+	}
+	seen := atomic.AddUint64(&l.sampled[level], 1)
+	return (seen-1)%uint64(n) == 0
+}
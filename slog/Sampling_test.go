@@ -0,0 +1,70 @@
+package slog
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestLoggerLevelSampling ensures each level keeps 1 in N lines according to its
+// rate, even under concurrent logging, and that levels without a rate keep everything.
+func TestLoggerLevelSampling(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(FINE)
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "")
+	logger.SetLevelSampling(map[LogLevel]int{
+		INFO:  10,
+		DEBUG: 1, // Keep-all, same as leaving it out
+		FINE:  100,
+	})
+
+	numGoroutines := 10
+	callsPerGoroutine := 1000
+	var wg sync.WaitGroup
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < callsPerGoroutine; j++ {
+				logger.Error("e")
+				logger.Info("i")
+				logger.Debug("d")
+				logger.Fine("f")
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := numGoroutines * callsPerGoroutine
+	expected := map[string]int{
+		"[ERROR] e": total, // Not in the map, so never sampled
+		"[INFO] i":  total / 10,
+		"[DEBUG] d": total,
+		"[FINE] f":  total / 100,
+	}
+	counts := make(map[string]int)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		counts[line]++
+	}
+	for line, count := range expected {
+		if counts[line] != count {
+			t.Errorf("Expected %d %q lines, got %d", count, line, counts[line])
+		}
+	}
+
+	// Turning sampling off keeps everything again.
+	buf.Reset()
+	logger.SetLevelSampling(nil)
+	for i := 0; i < 5; i++ {
+		logger.Fine("f")
+	}
+	if n := strings.Count(buf.String(), "[FINE] f"); n != 5 {
+		t.Errorf("Expected all 5 lines with sampling off, got %d", n)
+	}
+}
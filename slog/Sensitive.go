@@ -0,0 +1,115 @@
+package slog
+
+import (
+	"reflect"
+	"strings"
+)
+
+// redactedValue replaces the value of a sensitive field.
+const redactedValue = "***"
+
+// SetSensitiveFieldKeys makes the logger replace the value of any field whose key
+// matches one of keys with "***" before the entry reaches any output, so secrets
+// can't leak through structured fields whatever their value. The same goes for
+// values under matching keys in map values, at any depth, such as StrMap and
+// WithDiff fields, and for LogChange of a matching field, whose old and new values
+// are left out of the message too. Keys match case-insensitively; a leading or
+// trailing * matches any prefix or suffix, e.g.
+//
+//	logger.SetSensitiveFieldKeys("password", "token", "authorization", "*_secret")
+//
+// Calling it again replaces the keys; calling it with none turns redaction off.
+// It's thread-safe.
+func (l *Logger) SetSensitiveFieldKeys(keys ...string) {
+	var patterns []string
+	for _, key := range keys {
+		patterns = append(patterns, strings.ToLower(key))
+	}
+
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.sensitiveKeys = patterns
+}
+
+// isSensitive reports whether key matches one of the logger's sensitive keys.
+func (l *Logger) isSensitive(key string) bool {
+	l.settingsMutex.RLock()
+	patterns := l.sensitiveKeys
+	l.settingsMutex.RUnlock()
+	return isSensitiveKey(strings.ToLower(key), patterns)
+}
+
+// redactFields replaces the values of sensitive fields, and of sensitive keys in
+// map values, in place.
+func (l *Logger) redactFields(fields []Field) {
+	l.settingsMutex.RLock()
+	patterns := l.sensitiveKeys
+	l.settingsMutex.RUnlock()
+	if len(patterns) == 0 {
+		return
+	}
+	for i := range fields {
+		if isSensitiveKey(strings.ToLower(fields[i].Key), patterns) {
+			fields[i].Value = redactedValue
+		} else if redacted, ok := redactMapKeys(fields[i].Value, patterns); ok {
+			fields[i].Value = redacted
+		}
+	}
+}
+
+// redactMapKeys returns a copy of value, if it's a map holding sensitive keys at
+// any depth, with their values replaced, and true. The copy is a
+// map[string]interface{} keyed by the keys' text, so it can hold "***" whatever
+// the original value type; the caller's map is left alone.
+func redactMapKeys(value interface{}, patterns []string) (interface{}, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Map || v.IsNil() {
+		return value, false
+	}
+	redacted := make(map[string]interface{}, v.Len())
+	changed := false
+	for iter := v.MapRange(); iter.Next(); {
+		key := formatFieldValue(iter.Key().Interface())
+		elem := iter.Value().Interface()
+		if isSensitiveKey(strings.ToLower(key), patterns) {
+			redacted[key] = redactedValue
+			changed = true
+		} else if nested, ok := redactMapKeys(elem, patterns); ok {
+			redacted[key] = nested
+			changed = true
+		} else {
+			redacted[key] = elem
+		}
+	}
+	if !changed {
+		return value, false
+	}
+	return redacted, true
+}
+
+// isSensitiveKey reports whether the lowercased key matches one of patterns.
+func isSensitiveKey(key string, patterns []string) bool {
+	for _, pattern := range patterns {
+		prefix, suffix := strings.HasSuffix(pattern, "*"), strings.HasPrefix(pattern, "*")
+		core := strings.TrimSuffix(strings.TrimPrefix(pattern, "*"), "*")
+		switch {
+		case prefix && suffix:
+			if strings.Contains(key, core) {
+				return true
+			}
+		case prefix:
+			if strings.HasPrefix(key, core) {
+				return true
+			}
+		case suffix:
+			if strings.HasSuffix(key, core) {
+				return true
+			}
+		default:
+			if key == core {
+				return true
+			}
+		}
+	}
+	return false
+}
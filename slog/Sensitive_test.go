@@ -0,0 +1,100 @@
+package slog
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestLoggerSensitiveFieldKeys ensures fields with sensitive keys are redacted in
+// text output and in the entries structured sinks receive, for exact keys in any
+// case and for prefix/suffix patterns.
+func TestLoggerSensitiveFieldKeys(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	fields := []Field{
+		{Key: "user", Value: "alice"},
+		{Key: "Password", Value: "hunter2"},
+		{Key: "AUTHORIZATION", Value: "Bearer abc"},
+		{Key: "client_secret", Value: "s3cr3t"},
+		{Key: "token_id", Value: 7},
+		{Key: "tokens", Value: 3},
+	}
+	keys := []string{"password", "authorization", "*_secret", "token*"}
+
+	var buf bytes.Buffer
+	textLogger := newTestLogger(&buf, "Auth")
+	textLogger.SetSensitiveFieldKeys(keys...)
+	textLogger.WithFields(fields...).Info("Login")
+
+	expected := "[INFO][Auth] Login user=alice Password=*** AUTHORIZATION=*** client_secret=*** token_id=*** tokens=***\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	sinkLogger, sink := newCaptureLogger("Auth")
+	sinkLogger.SetSensitiveFieldKeys(keys...)
+	sinkLogger.WithFields(fields...).Info("Login")
+	sinkLogger.SetSensitiveFieldKeys()
+	sinkLogger.WithFields(fields[1]).Info("Redaction off")
+
+	entries := sink.Entries()
+	encoded := fieldsJSON(entries[0].Fields)
+	expectedJSON := `{"AUTHORIZATION":"***","Password":"***","client_secret":"***","token_id":"***","tokens":"***","user":"alice"}`
+	if encoded != expectedJSON {
+		t.Errorf("Expected %s, got %s", expectedJSON, encoded)
+	}
+	if entries[1].Fields[0].Value != "hunter2" {
+		t.Errorf("Expected no redaction after clearing the keys, got %v", entries[1].Fields)
+	}
+
+	// The fields given to WithFields are left alone.
+	if fields[1].Value != "hunter2" {
+		t.Errorf("Expected the caller's fields to be untouched, got %v", fields[1])
+	}
+}
+
+// TestLoggerSensitiveFieldKeysNested ensures sensitive keys are redacted inside
+// map values, StrMap and WithDiff fields, and in LogChange records, message included.
+func TestLoggerSensitiveFieldKeysNested(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	type credentials struct {
+		User     string
+		Password string
+	}
+	headers := map[string]string{"Authorization": "Bearer abc", "Accept": "*/*"}
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Auth")
+	logger.SetSensitiveFieldKeys("*password", "authorization", "*_secret")
+
+	logger.WithFields(StrMap("headers", headers)).Info("Request")
+	logger.WithFields(Field{Key: "config", Value: map[string]interface{}{
+		"db":         map[string]interface{}{"host": "db1", "Password": "hunter2"},
+		"api_secret": "s3cr3t",
+		"retries":    3,
+	}}).Info("Loaded")
+	logger.WithDiff("diff", credentials{"alice", "a1"}, credentials{"bob", "a2"}).Info("Rotated")
+	logger.LogChange(INFO, "db_password", "a1", "a2")
+	logger.LogChange(INFO, "pool_size", 5, 10)
+
+	expected := "[INFO][Auth] Request headers={Accept=*/*;Authorization=***}\n" +
+		"[INFO][Auth] Loaded config={api_secret=***;db={Password=***;host=db1};retries=3}\n" +
+		"[INFO][Auth] Rotated diff={Password=***;User=alice→bob}\n" +
+		"[INFO][Auth] db_password changed from *** to *** changed_field=db_password old=*** new=***\n" +
+		"[INFO][Auth] pool_size changed from 5 to 10 changed_field=pool_size old=5 new=10\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, buf.String())
+	}
+	if headers["Authorization"] != "Bearer abc" {
+		t.Errorf("Expected the caller's map to be left alone, got %v", headers)
+	}
+}
@@ -0,0 +1,70 @@
+package slog
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// NewSplitFileLogger creates a Logger that writes each level to its own files,
+// e.g. errors and warnings to errors.log as well as everything to app.log:
+//
+//	logger, err := slog.NewSplitFileLogger("App", map[slog.LogLevel][]string{
+//		slog.ERROR: {"errors.log", "app.log"},
+//		slog.WARN:  {"errors.log", "app.log"},
+//		slog.INFO:  {"app.log"},
+//		slog.DEBUG: {"app.log"},
+//	}, nil)
+//
+// Files are created if needed and opened for appending; levels sharing a path
+// share the file. Levels without an entry in spec go to fallback, or to os.Stdout
+// if it's nil, as with NewLogger. Close closes the files, but not fallback.
+func NewSplitFileLogger(component string, spec map[LogLevel][]string, fallback *os.File) (*Logger, error) {
+	files := make(splitFiles)
+	outputs := make(map[string]*log.Logger) // By the level's paths, joined
+	levelLoggers := make(map[LogLevel]*log.Logger, len(spec))
+	for level, paths := range spec {
+		key := strings.Join(paths, "\x00")
+		output, ok := outputs[key]
+		if !ok {
+			writers := make([]io.Writer, 0, len(paths))
+			for _, path := range paths {
+				file, ok := files[path]
+				if !ok {
+					var err error
+					file, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+					if err != nil {
+						files.Close()
+						return nil, fmt.Errorf("opening log file for %s: %w", level, err)
+					}
+					files[path] = file
+				}
+				writers = append(writers, file)
+			}
+			output = log.New(io.MultiWriter(writers...), "", 0) // Timestamps are added by write
+			outputs[key] = output
+		}
+		levelLoggers[level] = output
+	}
+
+	logger := NewLogger(component, fallback)
+	logger.levelLoggers = levelLoggers
+	logger.closer = files
+	return logger, nil
+}
+
+// splitFiles holds the files opened by NewSplitFileLogger, by path.
+type splitFiles map[string]*os.File
+
+// Close closes every file, returning the first error.
+func (f splitFiles) Close() error {
+	var firstErr error
+	for _, file := range f {
+		if err := file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
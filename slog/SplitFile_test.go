@@ -0,0 +1,79 @@
+package slog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNewSplitFileLogger ensures each level is appended to the files it's assigned
+// to, that files shared by several levels receive all of them, and that other
+// levels go to the fallback.
+func TestNewSplitFileLogger(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(DEBUG)
+
+	dir := t.TempDir()
+	errorsPath := filepath.Join(dir, "errors.log")
+	appPath := filepath.Join(dir, "app.log")
+
+	// Existing content must be kept.
+	if err := os.WriteFile(appPath, []byte("previous run\n"), 0644); err != nil {
+		t.Fatalf("Failed to seed app.log: %v", err)
+	}
+
+	fallback, err := os.Create(filepath.Join(dir, "other.log"))
+	if err != nil {
+		t.Fatalf("Failed to create the fallback file: %v", err)
+	}
+	defer fallback.Close()
+
+	logger, err := NewSplitFileLogger("App", map[LogLevel][]string{
+		ERROR: {errorsPath, appPath},
+		WARN:  {errorsPath, appPath},
+		INFO:  {appPath},
+	}, fallback)
+	if err != nil {
+		t.Fatalf("Expected NewSplitFileLogger to succeed, got %v", err)
+	}
+	logger.timestamps = false
+
+	logger.Error("Disk failed")
+	logger.Warn("Disk slow")
+	logger.Info("Started")
+	logger.WithFields(Field{Key: "user", Value: "alice"}).Debug("Loaded config")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Expected Close to succeed, got %v", err)
+	}
+
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{errorsPath, "[ERROR][App] Disk failed\n[WARN][App] Disk slow\n"},
+		{appPath, "previous run\n[ERROR][App] Disk failed\n[WARN][App] Disk slow\n[INFO][App] Started\n"},
+		{fallback.Name(), "[DEBUG][App] Loaded config user=alice\n"},
+	}
+	for _, tc := range testCases {
+		content, err := os.ReadFile(tc.path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", tc.path, err)
+		}
+		if string(content) != tc.expected {
+			t.Errorf("Expected %s to contain %q, got %q", filepath.Base(tc.path), tc.expected, content)
+		}
+	}
+}
+
+// TestNewSplitFileLoggerOpenError ensures an unwritable path is reported.
+func TestNewSplitFileLoggerOpenError(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing", "errors.log")
+	_, err := NewSplitFileLogger("App", map[LogLevel][]string{ERROR: {missing}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "ERROR") {
+		t.Errorf("Expected an error naming the level, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package slog
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// maxStackFrames bounds the frames in a stack field.
+const maxStackFrames = 32
+
+// stackOccurrence tracks one distinct ERROR message for SetFirstErrorStacks.
+type stackOccurrence struct {
+	first time.Time // When the occurrence that carried the stack was logged
+	count uint64    // Occurrences since then, including that one
+}
+
+// SetFirstErrorStacks makes the logger attach a stack field, with the caller's
+// stack trace, to the first ERROR line with a given message within window. Repeats
+// of that message within the window carry an occurrence field counting them
+// instead (2, 3, ...), so an error logged thousands of times shows its stack once.
+// The first occurrence after the window has passed carries the stack again.
+// A window of 0 (the default) turns stacks off.
+// It's thread-safe.
+func (l *Logger) SetFirstErrorStacks(window time.Duration) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.stackWindow = window
+}
+
+// stackFields returns the stack or occurrence field for an ERROR line with message.
+func (l *Logger) stackFields(level LogLevel, message string, t time.Time) []Field {
+	if level != ERROR {
+		return nil
+	}
+	l.settingsMutex.RLock()
+	window := l.stackWindow
+	l.settingsMutex.RUnlock()
+	if window <= 0 {
+		return nil
+	}
+
+	l.stackSeenMutex.Lock()
+	defer l.stackSeenMutex.Unlock()
+	if l.stackSeen == nil {
+		l.stackSeen = make(map[string]*stackOccurrence)
+	}
+	occurrence, ok := l.stackSeen[message]
+	if ok && t.Sub(occurrence.first) < window {
+		occurrence.count++
+		return []Field{{Key: "occurrence", Value: occurrence.count}}
+	}
+
+	// Forget expired messages before adding one, so the map doesn't grow without bound.
+	for seen, o := range l.stackSeen {
+		if t.Sub(o.first) >= window {
+			delete(l.stackSeen, seen)
+		}
+	}
+	l.stackSeen[message] = &stackOccurrence{first: t, count: 1}
+	return []Field{{Key: "stack", Value: callerStack()}}
+}
+
+// callerStack renders the stack of whoever called the logging method, skipping
+// the logger's own frames, one "function (file:line)" per line.
+func callerStack() string {
+	var pcs [maxStackFrames + 16]uintptr
+	n := runtime.Callers(2, pcs[:]) // Skip runtime.Callers and callerStack
+	frames := runtime.CallersFrames(pcs[:n])
+	var stack strings.Builder
+	written := 0
+	for written < maxStackFrames {
+		frame, more := frames.Next()
+		if !isLoggerMethod(frame.Function) {
+			if written > 0 {
+				stack.WriteByte('\n')
+			}
+			fmt.Fprintf(&stack, "%s (%s:%d)", frame.Function, frame.File, frame.Line)
+			written++
+		}
+		if !more {
+			break
+		}
+	}
+	return stack.String()
+}
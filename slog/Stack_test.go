@@ -0,0 +1,64 @@
+package slog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestLoggerFirstErrorStacks ensures only the first of N identical errors within
+// the window carries a stack, repeats carry a count, and the stack starts at the
+// logging call.
+func TestLoggerFirstErrorStacks(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetClock(nil)
+	})
+	SetGlobalMinLevel(INFO)
+	clock := &fakeClock{current: time.Date(2022, time.July, 4, 10, 0, 0, 0, time.UTC)}
+	SetClock(clock)
+
+	logger, sink := newCaptureLogger("DB")
+	logger.SetFirstErrorStacks(time.Minute)
+
+	const n = 5
+	for i := 0; i < n; i++ {
+		logger.Error("Connection reset")
+	}
+	logger.Error("Disk full")
+	logger.Warn("Connection reset") // Only ERROR lines get stacks
+	clock.Advance(time.Minute)
+	logger.Error("Connection reset")
+
+	entries := sink.Entries()
+	if len(entries) != n+3 {
+		t.Fatalf("Expected %d entries, got %d", n+3, len(entries))
+	}
+	withStack := []int{0, n, n + 2}
+	for _, i := range withStack {
+		stack, ok := fieldMap(entries[i])["stack"].(string)
+		if !ok {
+			t.Errorf("Entry %d (%q): expected a stack", i, entries[i].Message)
+			continue
+		}
+		if !strings.HasPrefix(stack, "github.com/giles-m-thompson/slog/slog.TestLoggerFirstErrorStacks (") {
+			t.Errorf("Entry %d: expected the stack to start at the test, got:\n%s", i, stack)
+		}
+		if strings.Contains(stack, "(*Logger)") {
+			t.Errorf("Entry %d: expected no logger frames in the stack, got:\n%s", i, stack)
+		}
+	}
+	for i := 1; i < n; i++ {
+		fields := fieldMap(entries[i])
+		if _, ok := fields["stack"]; ok {
+			t.Errorf("Entry %d: expected no stack on a repeat", i)
+		}
+		if fields["occurrence"] != uint64(i+1) {
+			t.Errorf("Entry %d: expected occurrence=%d, got %v", i, i+1, fields["occurrence"])
+		}
+	}
+	if len(entries[n+1].Fields) != 0 {
+		t.Errorf("Expected no stack fields on a WARN line, got %v", entries[n+1].Fields)
+	}
+}
@@ -0,0 +1,52 @@
+package slog
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// processStart is when the package was loaded, which Summary reports uptime from.
+var processStart = now()
+
+// SetSummaryLevel sets the level Summary logs at. The default is INFO.
+// It's thread-safe.
+func (l *Logger) SetSummaryLevel(level LogLevel) {
+	l.settingsMutex.Lock()
+	defer l.settingsMutex.Unlock()
+	l.summaryLevel = &level
+}
+
+// Summary logs one line summarising the run so far: how many lines were written
+// per level (count_error, count_warn, ...) through the Logger owning the output
+// and every Logger derived from it, e.g. by WithFields, the process uptime, and
+// had_errors, which is true if any ERROR line was written. It's written whatever
+// the global minimum level, so it's typically deferred in main:
+//
+//	logger := slog.NewLogger("App", nil)
+//	defer logger.Summary()
+func (l *Logger) Summary() {
+	l.settingsMutex.RLock()
+	level := INFO
+	if l.summaryLevel != nil {
+		level = *l.summaryLevel
+	}
+	l.settingsMutex.RUnlock()
+	if l.isClosed(level) {
+		return
+	}
+
+	owner := l.owner()
+	fields := make([]Field, 0, len(owner.tree)+2)
+	for counted := ERROR; counted <= FINE; counted++ {
+		count := atomic.LoadUint64(&owner.tree[counted])
+		fields = append(fields, Field{Key: "count_" + strings.ToLower(counted.String()), Value: count})
+	}
+	fields = append(fields,
+		Field{Key: "uptime", Value: now().Sub(processStart).Round(time.Millisecond).String()},
+		Field{Key: "had_errors", Value: atomic.LoadUint64(&owner.tree[ERROR]) > 0},
+	)
+	if entry, ok := l.newEntry(time.Time{}, level, "Summary", fields...); ok {
+		l.write(entry)
+	}
+}
@@ -0,0 +1,62 @@
+package slog
+
+import (
+	"testing"
+	"time"
+)
+
+// TestLoggerSummary ensures the summary reports the lines written per level,
+// the uptime and whether there were errors, and is written even when its level
+// is filtered out.
+func TestLoggerSummary(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	originalStart := processStart
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetClock(nil)
+		processStart = originalStart
+	})
+	SetGlobalMinLevel(INFO)
+	clock := &fakeClock{current: time.Date(2022, time.July, 4, 10, 0, 0, 0, time.UTC)}
+	SetClock(clock)
+	processStart = clock.Now()
+
+	logger, sink := newCaptureLogger("CI")
+	logger.Info("Step 1")
+	logger.Info("Step 2")
+	logger.Warn("Flaky test retried")
+	logger.Debug("Filtered out")
+	clock.Advance(90 * time.Second)
+
+	logger.Summary()
+	summary := sink.Entries()[3]
+	expected := map[string]interface{}{
+		"count_error": uint64(0),
+		"count_warn":  uint64(1),
+		"count_info":  uint64(2),
+		"count_debug": uint64(0),
+		"count_fine":  uint64(0),
+		"uptime":      "1m30s",
+		"had_errors":  false,
+	}
+	fields := fieldMap(summary)
+	if summary.Level != INFO || summary.Message != "Summary" || len(fields) != len(expected) {
+		t.Fatalf("Unexpected summary %v", summary)
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, fields[key])
+		}
+	}
+
+	// An error flips had_errors, even through a derived Logger; a filtered
+	// summary level is still written.
+	logger.WithFields(Field{Key: "step", Value: 3}).Error("Build failed")
+	logger.SetSummaryLevel(DEBUG)
+	logger.Summary()
+	entries := sink.Entries()
+	summary = entries[len(entries)-1]
+	if fields := fieldMap(summary); summary.Level != DEBUG || fields["had_errors"] != true || fields["count_error"] != uint64(1) {
+		t.Errorf("Expected a DEBUG summary with had_errors=true and count_error=1, got %v %v", summary.Level, summary.Fields)
+	}
+}
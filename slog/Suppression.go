@@ -0,0 +1,129 @@
+package slog
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Suppression is a handle to a pattern added with AddSuppressionPattern or
+// AddSuppressionRegex. It counts the lines it dropped and can be removed.
+type Suppression struct {
+	dropped uint64 // Kept first so it is 64-bit aligned for atomic access on 32-bit platforms
+	removed uint32 // Set to 1 by Remove; accessed atomically
+
+	logger    *Logger
+	substring string
+	regex     *regexp.Regexp
+}
+
+// AddSuppressionPattern drops every line whose formatted message contains substr,
+// e.g. to silence a noisy third-party library without raising the level.
+// It's thread-safe.
+func (l *Logger) AddSuppressionPattern(substr string) *Suppression {
+	return l.addSuppression(&Suppression{logger: l, substring: substr})
+}
+
+// AddSuppressionRegex drops every line whose formatted message matches re.
+// It's thread-safe.
+func (l *Logger) AddSuppressionRegex(re *regexp.Regexp) *Suppression {
+	return l.addSuppression(&Suppression{logger: l, regex: re})
+}
+
+// addSuppression registers s with l and returns it.
+func (l *Logger) addSuppression(s *Suppression) *Suppression {
+	l.suppressionsMutex.Lock()
+	defer l.suppressionsMutex.Unlock()
+	l.suppressions = append(l.suppressions, s)
+	return s
+}
+
+// ClearSuppressions removes every suppression from this Logger. Those added to
+// it are removed as if Remove had been called on each; those inherited from the
+// Logger it was derived from stop applying here but keep working there.
+// It's thread-safe.
+func (l *Logger) ClearSuppressions() {
+	l.suppressionsMutex.Lock()
+	defer l.suppressionsMutex.Unlock()
+	for _, s := range l.suppressions {
+		if s.logger == l {
+			atomic.StoreUint32(&s.removed, 1)
+		}
+	}
+	l.suppressions = nil
+}
+
+// SuppressedStats returns how many lines each current suppression has dropped,
+// keyed by its substring or regular expression.
+// It's thread-safe.
+func (l *Logger) SuppressedStats() map[string]uint64 {
+	l.suppressionsMutex.RLock()
+	defer l.suppressionsMutex.RUnlock()
+	stats := make(map[string]uint64, len(l.suppressions))
+	for _, s := range l.suppressions {
+		if !s.isRemoved() {
+			stats[s.String()] += s.Dropped()
+		}
+	}
+	return stats
+}
+
+// Remove stops s from dropping lines on the Logger it was added to, and on the
+// Loggers derived from it, which share the handle.
+// It's thread-safe.
+func (s *Suppression) Remove() {
+	atomic.StoreUint32(&s.removed, 1)
+	l := s.logger
+	l.suppressionsMutex.Lock()
+	defer l.suppressionsMutex.Unlock()
+	for i, existing := range l.suppressions {
+		if existing == s {
+			l.suppressions = append(l.suppressions[:i:i], l.suppressions[i+1:]...)
+			return
+		}
+	}
+}
+
+// Dropped returns how many lines s has dropped.
+// It's thread-safe.
+func (s *Suppression) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// String returns the substring or regular expression s matches.
+func (s *Suppression) String() string {
+	if s.regex != nil {
+		return s.regex.String()
+	}
+	return s.substring
+}
+
+// isRemoved reports whether s has been removed.
+func (s *Suppression) isRemoved() bool {
+	return atomic.LoadUint32(&s.removed) != 0
+}
+
+// matches reports whether message should be dropped by s.
+func (s *Suppression) matches(message string) bool {
+	if s.isRemoved() {
+		return false
+	}
+	if s.regex != nil {
+		return s.regex.MatchString(message)
+	}
+	return strings.Contains(message, s.substring)
+}
+
+// isSuppressed reports whether message matches any of the logger's suppressions,
+// counting the drop against the first one that matches.
+func (l *Logger) isSuppressed(message string) bool {
+	l.suppressionsMutex.RLock()
+	defer l.suppressionsMutex.RUnlock()
+	for _, s := range l.suppressions {
+		if s.matches(message) {
+			atomic.AddUint64(&s.dropped, 1)
+			return true
+		}
+	}
+	return false
+}
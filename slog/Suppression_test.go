@@ -0,0 +1,121 @@
+package slog
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestLoggerSuppression ensures messages matching a substring or regex suppression are
+// dropped and counted, and that removing or clearing suppressions lets them through again.
+func TestLoggerSuppression(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Vendor")
+	heartbeat := logger.AddSuppressionPattern("heartbeat ok")
+	retries := logger.AddSuppressionRegex(regexp.MustCompile(`^retry #\d+ scheduled$`))
+
+	logger.Info("heartbeat ok from %s", "node-1")
+	logger.Info("heartbeat ok from %s", "node-2")
+	logger.Warn("retry #%d scheduled", 3)
+	logger.Warn("retry #%d scheduled after timeout", 4) // Doesn't match the anchored regex
+	logger.Info("Connected")
+
+	expected := "[WARN][Vendor] retry #4 scheduled after timeout\n[INFO][Vendor] Connected"
+	if output := strings.TrimSpace(buf.String()); output != expected {
+		t.Errorf("Expected output:\n%s\nGot:\n%s", expected, output)
+	}
+
+	if heartbeat.Dropped() != 2 || retries.Dropped() != 1 {
+		t.Errorf("Expected 2 and 1 dropped lines, got %d and %d", heartbeat.Dropped(), retries.Dropped())
+	}
+	stats := logger.SuppressedStats()
+	if stats["heartbeat ok"] != 2 || stats[`^retry #\d+ scheduled$`] != 1 || len(stats) != 2 {
+		t.Errorf("Expected a summary of 2 heartbeat and 1 retry drops, got %v", stats)
+	}
+
+	// Removing one suppression only lets its own lines through.
+	buf.Reset()
+	heartbeat.Remove()
+	logger.Info("heartbeat ok")
+	logger.Warn("retry #5 scheduled")
+	if output := strings.TrimSpace(buf.String()); output != "[INFO][Vendor] heartbeat ok" {
+		t.Errorf("Expected only the heartbeat line after Remove, got %q", output)
+	}
+
+	buf.Reset()
+	logger.ClearSuppressions()
+	logger.Warn("retry #6 scheduled")
+	if output := strings.TrimSpace(buf.String()); output != "[WARN][Vendor] retry #6 scheduled" {
+		t.Errorf("Expected nothing suppressed after ClearSuppressions, got %q", output)
+	}
+	if stats := logger.SuppressedStats(); len(stats) != 0 {
+		t.Errorf("Expected an empty summary after ClearSuppressions, got %v", stats)
+	}
+}
+
+// TestSuppressionRemoveDerived ensures removing a suppression, directly or with
+// ClearSuppressions, also stops it dropping lines on Loggers derived before.
+func TestSuppressionRemoveDerived(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Vendor")
+	heartbeat := logger.AddSuppressionPattern("heartbeat")
+	logger.AddSuppressionPattern("retry")
+	child := logger.WithFields(Field{Key: "conn", Value: 1})
+
+	child.Info("heartbeat ok")
+	heartbeat.Remove()
+	child.Info("heartbeat ok")
+	child.Info("retry scheduled")
+	logger.ClearSuppressions()
+	child.Info("retry scheduled")
+
+	expected := "[INFO][Vendor] heartbeat ok conn=1\n[INFO][Vendor] retry scheduled conn=1\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+	if stats := child.SuppressedStats(); len(stats) != 0 {
+		t.Errorf("Expected no active suppressions on the child, got %v", stats)
+	}
+}
+
+// TestClearSuppressionsDerived ensures clearing a derived Logger's suppressions
+// leaves those it inherited working on the parent, while removing its own.
+func TestClearSuppressionsDerived(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+	})
+	SetGlobalMinLevel(INFO)
+
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, "Vendor")
+	logger.AddSuppressionPattern("heartbeat")
+	child := logger.WithFields(Field{Key: "conn", Value: 1})
+	child.AddSuppressionPattern("retry")
+
+	child.ClearSuppressions()
+	logger.Info("heartbeat ok")
+	child.Info("heartbeat ok")
+	child.Info("retry scheduled")
+
+	expected := "[INFO][Vendor] heartbeat ok conn=1\n[INFO][Vendor] retry scheduled conn=1\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+	if stats := logger.SuppressedStats(); len(stats) != 1 || stats["heartbeat"] != 1 {
+		t.Errorf("Expected the parent's suppression to keep working, got %v", stats)
+	}
+}
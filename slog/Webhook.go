@@ -0,0 +1,310 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Webhook defaults, each of which can be changed with a WebhookOption.
+const (
+	defaultWebhookTemplate = `{"text": {{if .Suppressed}}{{json (printf "%s (%d more suppressed)" .Text .Suppressed)}}{{else}}{{json .Text}}{{end}}}`
+	defaultWebhookLimit    = 10
+	defaultWebhookWindow   = time.Minute
+	defaultWebhookAttempts = 3
+	defaultWebhookBackoff  = time.Second
+	webhookTimeout         = 10 * time.Second
+	webhookQueueSize       = 64
+)
+
+// webhookCloseTimeout is how long Close waits for queued notifications to be sent
+// before giving up on them. It's a variable so tests can shorten it.
+var webhookCloseTimeout = 5 * time.Second
+
+// WebhookData is what a webhook template (see WebhookTemplate) is executed with.
+type WebhookData struct {
+	Time       time.Time
+	Level      string
+	Component  string
+	Message    string
+	Fields     map[string]interface{}
+	Meta       map[string]interface{} // The line's metadata (see WithMeta)
+	Text       string                 // The line as written to the text output, without the timestamp
+	Suppressed int                    // Notifications dropped by the rate limit since the previous one
+}
+
+// WebhookOption configures NotifyWebhook.
+type WebhookOption func(*webhookNotifier)
+
+// WebhookTemplate sets the text/template that renders each payload from a
+// WebhookData. It must produce JSON; the json function encodes a value as JSON,
+// e.g. `{"content": {{json .Message}}}` for Discord. The default,
+// `{"text": {{json .Text}}}` plus a note of any suppressed notifications, suits
+// Slack and most generic webhooks.
+func WebhookTemplate(text string) WebhookOption {
+	return func(n *webhookNotifier) { n.templateText = text }
+}
+
+// WebhookRateLimit allows at most max notifications per window; the rest are
+// dropped and counted in the next notification's Suppressed. The default is 10
+// per minute.
+func WebhookRateLimit(max int, window time.Duration) WebhookOption {
+	return func(n *webhookNotifier) { n.limit, n.window = max, window }
+}
+
+// WebhookRetries makes up to attempts POSTs per notification, waiting backoff
+// before the second and doubling it each time after. The default is 3 attempts,
+// starting at 1 second. Only network errors, 429 and 5xx responses are retried.
+func WebhookRetries(attempts int, backoff time.Duration) WebhookOption {
+	return func(n *webhookNotifier) { n.attempts, n.backoff = attempts, backoff }
+}
+
+// WebhookClient sets the HTTP client used for the POSTs. The default times out after 10 seconds.
+func WebhookClient(client *http.Client) WebhookOption {
+	return func(n *webhookNotifier) { n.client = client }
+}
+
+// NotifyWebhook makes the logger also POST each line at minLevel or more severe
+// to url as a JSON payload, e.g. to alert a Slack channel on errors:
+//
+//	err := logger.NotifyWebhook(slackURL, slog.ERROR, slog.WebhookRateLimit(5, time.Minute))
+//
+// Notifications are sent in the background, one at a time, so logging never
+// waits for the webhook. They are rate limited, so an incident doesn't flood the
+// channel, and retried on failure (see the options for the defaults). A
+// notification that fails, or that is dropped because too many are queued, goes
+// to the error handler. Calling it again replaces the previous webhook; passing
+// an empty url stops notifying. The webhook belongs to the Logger that owns the
+// output, so it applies to every Logger derived from it, whenever they were
+// derived. Closing that Logger stops it, after sending what's queued within 5
+// seconds; closing a derived Logger leaves it running.
+// It returns an error if the template is invalid.
+// It's thread-safe.
+func (l *Logger) NotifyWebhook(url string, minLevel LogLevel, opts ...WebhookOption) error {
+	var notifier *webhookNotifier
+	if url != "" {
+		notifier = &webhookNotifier{
+			url:          url,
+			minLevel:     minLevel,
+			templateText: defaultWebhookTemplate,
+			limit:        defaultWebhookLimit,
+			window:       defaultWebhookWindow,
+			attempts:     defaultWebhookAttempts,
+			backoff:      defaultWebhookBackoff,
+			client:       &http.Client{Timeout: webhookTimeout},
+		}
+		for _, opt := range opts {
+			opt(notifier)
+		}
+		tmpl, err := template.New("webhook").Funcs(template.FuncMap{"json": webhookJSON}).Parse(notifier.templateText)
+		if err != nil {
+			return fmt.Errorf("webhook: %w", err)
+		}
+		notifier.template = tmpl
+		notifier.queue = make(chan []byte, webhookQueueSize)
+		notifier.done = make(chan struct{})
+		notifier.ctx, notifier.abandon = context.WithCancel(context.Background())
+		go notifier.run()
+	}
+
+	owner := l.owner()
+	owner.settingsMutex.Lock()
+	previous := owner.webhook
+	owner.webhook = notifier
+	owner.settingsMutex.Unlock()
+	if previous != nil {
+		previous.stop()
+	}
+	return nil
+}
+
+// currentWebhook returns the webhook set on the Logger owning the output, if any.
+func (l *Logger) currentWebhook() *webhookNotifier {
+	owner := l.owner()
+	owner.settingsMutex.RLock()
+	defer owner.settingsMutex.RUnlock()
+	return owner.webhook
+}
+
+// notifyWebhook hands the entries at the webhook's level to the logger's webhook,
+// if any. lines holds each entry's text line, or is nil if they weren't built.
+func (l *Logger) notifyWebhook(entries []Entry, lines []string) {
+	notifier := l.currentWebhook()
+	if notifier == nil {
+		return
+	}
+	for i, entry := range entries {
+		if entry.Level > notifier.minLevel {
+			continue
+		}
+		if lines != nil {
+			notifier.notify(entry, lines[i])
+		} else {
+			notifier.notify(entry, l.formatText(entry))
+		}
+	}
+}
+
+// webhookNotifier renders and queues notifications, and POSTs them from its own goroutine.
+type webhookNotifier struct {
+	url          string
+	minLevel     LogLevel
+	templateText string
+	template     *template.Template
+	limit        int
+	window       time.Duration
+	attempts     int
+	backoff      time.Duration
+	client       *http.Client
+
+	mutex       sync.Mutex // Guards the fields below and sends to queue
+	windowStart time.Time
+	sent        int // Notifications queued in the current window
+	suppressed  int // Notifications dropped by the rate limit since the last one queued
+	stopped     bool
+
+	queue   chan []byte        // Rendered payloads waiting to be POSTed
+	done    chan struct{}      // Closed when run has sent everything queued
+	ctx     context.Context    // Cancelled when stop gives up on the queued payloads
+	abandon context.CancelFunc // Cancels ctx
+	dropped int                // Payloads given up on; written by run, read by stop after done
+}
+
+// notify queues a notification for entry unless the rate limit has been reached.
+func (n *webhookNotifier) notify(entry Entry, text string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.stopped {
+		return
+	}
+	if t := now(); n.windowStart.IsZero() || !t.Before(n.windowStart.Add(n.window)) {
+		n.windowStart, n.sent = t, 0
+	}
+	if n.sent >= n.limit {
+		n.suppressed++
+		return
+	}
+
+	data := WebhookData{
+		Time:       entry.Time,
+		Level:      entry.Level.String(),
+		Component:  entry.Component,
+		Message:    entry.Message,
+		Fields:     make(map[string]interface{}, len(entry.Fields)),
+		Meta:       make(map[string]interface{}, len(entry.Meta)),
+		Text:       text,
+		Suppressed: n.suppressed,
+	}
+	for _, field := range entry.Fields {
+		data.Fields[field.Key] = field.Value
+	}
+	for _, field := range entry.Meta {
+		data.Meta[field.Key] = field.Value
+	}
+	var payload bytes.Buffer
+	if err := n.template.Execute(&payload, data); err != nil {
+		reportError(fmt.Errorf("webhook: rendering the payload: %w", err))
+		return
+	}
+	if !json.Valid(payload.Bytes()) {
+		reportError(fmt.Errorf("webhook: the template produced invalid JSON: %s", payload.String()))
+		return
+	}
+
+	select {
+	case n.queue <- payload.Bytes():
+		n.sent++
+		n.suppressed = 0
+	default:
+		reportError(fmt.Errorf("webhook: %d notifications already queued, dropped one", webhookQueueSize))
+	}
+}
+
+// run POSTs the queued payloads until stop is called, counting those left once
+// stop gives up on them.
+func (n *webhookNotifier) run() {
+	defer close(n.done)
+	for payload := range n.queue {
+		if n.ctx.Err() != nil {
+			n.dropped++
+			continue
+		}
+		if err := n.post(payload); err != nil {
+			if n.ctx.Err() != nil {
+				n.dropped++
+				continue
+			}
+			reportError(err)
+		}
+	}
+}
+
+// post POSTs payload, retrying as configured.
+func (n *webhookNotifier) post(payload []byte) error {
+	var lastErr error
+	backoff := n.backoff
+	for attempt := 1; attempt <= n.attempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(backoff):
+			case <-n.ctx.Done():
+				return n.ctx.Err()
+			}
+			backoff *= 2
+		}
+		request, err := http.NewRequestWithContext(n.ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("webhook: %w", err)
+		}
+		request.Header.Set("Content-Type", "application/json")
+		response, err := n.client.Do(request)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		io.Copy(ioutil.Discard, response.Body) // Lets the connection be reused
+		response.Body.Close()
+		if response.StatusCode/100 == 2 {
+			return nil
+		}
+		lastErr = fmt.Errorf("unexpected status %s", response.Status)
+		if response.StatusCode != http.StatusTooManyRequests && response.StatusCode < 500 {
+			break
+		}
+	}
+	return fmt.Errorf("webhook: notification dropped: %w", lastErr)
+}
+
+// stop stops queueing notifications and waits up to webhookCloseTimeout for the
+// queued ones to be sent, then gives up on the rest and reports how many.
+func (n *webhookNotifier) stop() {
+	n.mutex.Lock()
+	if !n.stopped {
+		n.stopped = true
+		close(n.queue)
+	}
+	n.mutex.Unlock()
+
+	select {
+	case <-n.done:
+		return
+	case <-time.After(webhookCloseTimeout):
+	}
+	n.abandon()
+	<-n.done
+	if n.dropped > 0 {
+		reportError(fmt.Errorf("webhook: gave up on %d notifications still unsent after %v", n.dropped, webhookCloseTimeout))
+	}
+}
+
+// webhookJSON encodes value for a webhook template (see marshalPrintable).
+func webhookJSON(value interface{}) string {
+	return string(marshalPrintable(value))
+}
@@ -0,0 +1,234 @@
+package slog
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookServer is a stub webhook that records the payloads it receives and
+// answers with the given statuses in turn, then 200.
+type webhookServer struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	payloads []string
+	statuses []int
+}
+
+func newWebhookServer(t *testing.T, statuses ...int) *webhookServer {
+	server := &webhookServer{statuses: statuses}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		server.payloads = append(server.payloads, string(body))
+		if len(server.statuses) > 0 {
+			w.WriteHeader(server.statuses[0])
+			server.statuses = server.statuses[1:]
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (s *webhookServer) Payloads() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.payloads...)
+}
+
+// TestLoggerNotifyWebhook ensures severe lines are POSTed with the default
+// payload, and that the rate limit drops and then reports the excess.
+func TestLoggerNotifyWebhook(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		SetClock(nil)
+	})
+	SetGlobalMinLevel(INFO)
+	clock := &fakeClock{current: time.Date(2022, time.July, 4, 10, 20, 30, 0, time.UTC)}
+	SetClock(clock)
+	errs := recordErrors(t)
+
+	server := newWebhookServer(t)
+	var buffer bytes.Buffer
+	logger := newTestLogger(&buffer, "Billing")
+	if err := logger.NotifyWebhook(server.URL, ERROR, WebhookRateLimit(2, time.Minute)); err != nil {
+		t.Fatalf("NotifyWebhook failed: %v", err)
+	}
+
+	logger.Warn("Card expiring")
+	for i := 1; i <= 4; i++ {
+		logger.Error("Charge %d failed", i)
+	}
+	clock.Advance(time.Minute)
+	logger.Error("Charge 5 failed")
+	logger.Close()
+
+	expected := []string{
+		`{"text": "[ERROR][Billing] Charge 1 failed"}`,
+		`{"text": "[ERROR][Billing] Charge 2 failed"}`,
+		`{"text": "[ERROR][Billing] Charge 5 failed (2 more suppressed)"}`,
+	}
+	payloads := server.Payloads()
+	if strings.Join(payloads, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected payloads %q, got %q", expected, payloads)
+	}
+	if reported := errs.Errors(); len(reported) != 0 {
+		t.Errorf("Expected no errors, got %v", reported)
+	}
+	if !strings.Contains(buffer.String(), "Charge 4 failed") {
+		t.Errorf("Expected rate-limited lines to still be logged, got %q", buffer.String())
+	}
+}
+
+// TestLoggerNotifyWebhookTemplateAndRetries ensures a custom template is used,
+// with the line's fields and metadata, and failed POSTs are retried.
+func TestLoggerNotifyWebhookTemplateAndRetries(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() { SetGlobalMinLevel(originalLevel) })
+	SetGlobalMinLevel(INFO)
+	errs := recordErrors(t)
+
+	server := newWebhookServer(t, http.StatusBadGateway)
+	var buffer bytes.Buffer
+	logger := newTestLogger(&buffer, "Orders")
+	err := logger.NotifyWebhook(server.URL, WARN,
+		WebhookTemplate(`{"content": {{json .Message}}, "level": {{json .Level}}, "order": {{json .Fields.order}}, "account": {{json .Meta.account_id}}}`),
+		WebhookRetries(3, time.Millisecond))
+	if err != nil {
+		t.Fatalf("NotifyWebhook failed: %v", err)
+	}
+
+	logger.WithFields(Field{Key: "order", Value: 42}).WithMeta("account_id", "a-7").Warn("Stock low")
+	logger.Info("Not severe enough")
+	logger.Close()
+
+	expected := `{"content": "Stock low", "level": "WARN", "order": 42, "account": "a-7"}`
+	payloads := server.Payloads()
+	if len(payloads) != 2 || payloads[0] != expected || payloads[1] != expected {
+		t.Errorf("Expected %q sent twice, got %q", expected, payloads)
+	}
+	if reported := errs.Errors(); len(reported) != 0 {
+		t.Errorf("Expected the retry to succeed, got %v", reported)
+	}
+}
+
+// TestLoggerNotifyWebhookFailure ensures a webhook that keeps failing is reported
+// to the error handler without affecting logging, and that an invalid template is
+// rejected.
+func TestLoggerNotifyWebhookFailure(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() { SetGlobalMinLevel(originalLevel) })
+	SetGlobalMinLevel(INFO)
+	errs := recordErrors(t)
+
+	server := newWebhookServer(t, http.StatusInternalServerError, http.StatusInternalServerError)
+	var buffer bytes.Buffer
+	logger := newTestLogger(&buffer, "Orders")
+	if err := logger.NotifyWebhook(server.URL, ERROR, WebhookRetries(2, time.Millisecond)); err != nil {
+		t.Fatalf("NotifyWebhook failed: %v", err)
+	}
+	logger.Error("Payment gateway down")
+	logger.Close()
+
+	if buffer.String() != "[ERROR][Orders] Payment gateway down\n" {
+		t.Errorf("Expected the line to be logged, got %q", buffer.String())
+	}
+	if len(server.Payloads()) != 2 {
+		t.Errorf("Expected 2 attempts, got %d", len(server.Payloads()))
+	}
+	reported := errs.Errors()
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "500") {
+		t.Errorf("Expected the failure to be reported, got %v", reported)
+	}
+
+	if err := logger.NotifyWebhook(server.URL, ERROR, WebhookTemplate(`{"text": {{.Message}`)); err == nil {
+		t.Error("Expected an invalid template to be rejected")
+	}
+}
+
+// TestLoggerNotifyWebhookDerived ensures derived Loggers notify through the
+// webhook of the Logger they were derived from, including those derived before it
+// was set, and that closing one leaves the webhook running.
+func TestLoggerNotifyWebhookDerived(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	t.Cleanup(func() { SetGlobalMinLevel(originalLevel) })
+	SetGlobalMinLevel(INFO)
+	errs := recordErrors(t)
+
+	server := newWebhookServer(t)
+	var buffer bytes.Buffer
+	logger := newTestLogger(&buffer, "Billing")
+	early := logger.WithFields(Field{Key: "stage", Value: "early"})
+	if err := logger.NotifyWebhook(server.URL, ERROR, WebhookTemplate(`{"text": {{json .Text}}}`)); err != nil {
+		t.Fatalf("NotifyWebhook failed: %v", err)
+	}
+	late := logger.WithFields(Field{Key: "stage", Value: "late"})
+
+	early.Error("Charge 1 failed")
+	late.Error("Charge 2 failed")
+	late.Close()
+	logger.Error("Charge 3 failed")
+	early.Error("Charge 4 failed")
+	logger.Close()
+
+	expected := []string{
+		`{"text": "[ERROR][Billing] Charge 1 failed stage=early"}`,
+		`{"text": "[ERROR][Billing] Charge 2 failed stage=late"}`,
+		`{"text": "[ERROR][Billing] Charge 3 failed"}`,
+		`{"text": "[ERROR][Billing] Charge 4 failed stage=early"}`,
+	}
+	payloads := server.Payloads()
+	if strings.Join(payloads, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected payloads %q, got %q", expected, payloads)
+	}
+	if reported := errs.Errors(); len(reported) != 0 {
+		t.Errorf("Expected no errors, got %v", reported)
+	}
+}
+
+// TestLoggerNotifyWebhookCloseTimeout ensures Close gives up on a webhook that
+// doesn't answer instead of waiting for it, and reports the unsent notifications.
+func TestLoggerNotifyWebhookCloseTimeout(t *testing.T) {
+	originalLevel := GetGlobalMinLevel()
+	originalTimeout := webhookCloseTimeout
+	t.Cleanup(func() {
+		SetGlobalMinLevel(originalLevel)
+		webhookCloseTimeout = originalTimeout
+	})
+	SetGlobalMinLevel(INFO)
+	webhookCloseTimeout = 50 * time.Millisecond
+	errs := recordErrors(t)
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	var buffer bytes.Buffer
+	logger := newTestLogger(&buffer, "Orders")
+	if err := logger.NotifyWebhook(server.URL, ERROR); err != nil {
+		t.Fatalf("NotifyWebhook failed: %v", err)
+	}
+	logger.Error("Payment gateway down")
+	logger.Error("Payment gateway still down")
+
+	start := time.Now()
+	logger.Close()
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected Close to give up on the webhook, it took %v", elapsed)
+	}
+	reported := errs.Errors()
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "gave up on 2 notifications") {
+		t.Errorf("Expected the unsent notifications to be reported, got %v", reported)
+	}
+}